@@ -0,0 +1,82 @@
+// Пакет bookingstatus описывает жизненный цикл бронирования:
+// pending -> confirmed -> seated -> completed, а также cancelled и no_show.
+package bookingstatus
+
+import (
+	"errors"
+	"fmt"
+)
+
+type Status string
+
+const (
+	Pending   Status = "pending"
+	Confirmed Status = "confirmed"
+	Seated    Status = "seated"
+	Completed Status = "completed"
+	Cancelled Status = "cancelled"
+	NoShow    Status = "no_show"
+)
+
+var (
+	ErrInvalidStatus     = errors.New("invalid booking status")
+	ErrInvalidTransition = errors.New("invalid booking status transition")
+)
+
+// Статусы, при которых бронирование занимает столик
+var Active = []Status{Pending, Confirmed, Seated}
+
+// Допустимые переходы; статусы без исходящих переходов - конечные
+var transitions = map[Status][]Status{
+	Pending:   {Confirmed, Cancelled, NoShow},
+	Confirmed: {Seated, Cancelled, NoShow},
+	Seated:    {Completed},
+}
+
+// Разобрать статус из строки запроса
+func Parse(value string) (Status, error) {
+	status := Status(value)
+	if !status.Valid() {
+		return "", fmt.Errorf("%w: %q", ErrInvalidStatus, value)
+	}
+	return status, nil
+}
+
+func (s Status) Valid() bool {
+	switch s {
+	case Pending, Confirmed, Seated, Completed, Cancelled, NoShow:
+		return true
+	}
+	return false
+}
+
+// Занимает ли бронирование в этом статусе столик
+func (s Status) IsActive() bool {
+	for _, active := range Active {
+		if s == active {
+			return true
+		}
+	}
+	return false
+}
+
+// Можно ли перевести бронирование из статуса from в статус to
+func CanTransition(from, to Status) bool {
+	for _, next := range transitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Проверить переход, вернув ErrInvalidTransition с описанием для недопустимого
+func Transition(from, to Status) error {
+	if !to.Valid() {
+		return fmt.Errorf("%w: %q", ErrInvalidStatus, to)
+	}
+	if !CanTransition(from, to) {
+		return fmt.Errorf("%w: cannot change status from %s to %s", ErrInvalidTransition, from, to)
+	}
+	return nil
+}
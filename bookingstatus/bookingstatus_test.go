@@ -0,0 +1,103 @@
+package bookingstatus
+
+import (
+	"errors"
+	"testing"
+)
+
+var all = []Status{Pending, Confirmed, Seated, Completed, Cancelled, NoShow}
+
+// Полная матрица переходов: разрешены только перечисленные пары
+func TestCanTransitionMatrix(t *testing.T) {
+	allowed := map[[2]Status]bool{
+		{Pending, Confirmed}:   true,
+		{Pending, Cancelled}:   true,
+		{Pending, NoShow}:      true,
+		{Confirmed, Seated}:    true,
+		{Confirmed, Cancelled}: true,
+		{Confirmed, NoShow}:    true,
+		{Seated, Completed}:    true,
+	}
+
+	for _, from := range all {
+		for _, to := range all {
+			want := allowed[[2]Status{from, to}]
+			if got := CanTransition(from, to); got != want {
+				t.Errorf("CanTransition(%s, %s) = %v, want %v", from, to, got, want)
+			}
+		}
+	}
+}
+
+func TestTransition(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    Status
+		to      Status
+		wantErr error
+	}{
+		{"confirm pending", Pending, Confirmed, nil},
+		{"seat confirmed", Confirmed, Seated, nil},
+		{"complete seated", Seated, Completed, nil},
+		{"cancel confirmed", Confirmed, Cancelled, nil},
+		{"no-show pending", Pending, NoShow, nil},
+		{"seat pending", Pending, Seated, ErrInvalidTransition},
+		{"complete confirmed", Confirmed, Completed, ErrInvalidTransition},
+		{"cancel seated", Seated, Cancelled, ErrInvalidTransition},
+		{"reopen cancelled", Cancelled, Pending, ErrInvalidTransition},
+		{"reopen completed", Completed, Seated, ErrInvalidTransition},
+		{"seat no-show", NoShow, Seated, ErrInvalidTransition},
+		{"same status", Pending, Pending, ErrInvalidTransition},
+		{"unknown target", Pending, Status("archived"), ErrInvalidStatus},
+		{"empty target", Confirmed, Status(""), ErrInvalidStatus},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Transition(tt.from, tt.to)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("Transition(%s, %s) = %v, want nil", tt.from, tt.to, err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Transition(%s, %s) = %v, want %v", tt.from, tt.to, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// Конечные статусы не имеют исходящих переходов
+func TestFinalStatuses(t *testing.T) {
+	for _, from := range []Status{Completed, Cancelled, NoShow} {
+		for _, to := range all {
+			if CanTransition(from, to) {
+				t.Errorf("final status %s allows transition to %s", from, to)
+			}
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	for _, status := range all {
+		got, err := Parse(string(status))
+		if err != nil || got != status {
+			t.Errorf("Parse(%q) = %q, %v", status, got, err)
+		}
+	}
+	for _, value := range []string{"", "booked", "Pending"} {
+		if _, err := Parse(value); !errors.Is(err, ErrInvalidStatus) {
+			t.Errorf("Parse(%q) error = %v, want ErrInvalidStatus", value, err)
+		}
+	}
+}
+
+func TestIsActive(t *testing.T) {
+	active := map[Status]bool{Pending: true, Confirmed: true, Seated: true}
+	for _, status := range all {
+		if got := status.IsActive(); got != active[status] {
+			t.Errorf("%s.IsActive() = %v, want %v", status, got, active[status])
+		}
+	}
+}
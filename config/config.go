@@ -3,11 +3,19 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
 
 	"github.com/joho/godotenv"
 	"gopkg.in/yaml.v3"
 )
 
+// Длительность бронирования по умолчанию для компаний до MaxGuests человек;
+// MaxGuests = 0 - для компаний любого размера
+type DurationRule struct {
+	MaxGuests int `yaml:"max_guests"`
+	Minutes   int `yaml:"minutes"`
+}
+
 type Config struct {
 	App struct {
 		Env  string `yaml:"env"`
@@ -22,8 +30,34 @@ type Config struct {
 		SSLMode  string `yaml:"sslmode"`
 	} `yaml:"database"`
 	JWT struct {
-		Secret string `yaml:"secret"`
+		Secret             string `yaml:"secret"`
+		AccessTokenMinutes int    `yaml:"access_token_minutes"` // время жизни access-токена
+		RefreshTokenDays   int    `yaml:"refresh_token_days"`   // время жизни refresh-токена
 	} `yaml:"jwt"`
+	Mail struct {
+		Driver string `yaml:"driver"` // log или file
+		Dir    string `yaml:"dir"`    // каталог писем для драйвера file
+		From   string `yaml:"from"`
+		AppURL string `yaml:"app_url"` // адрес фронтенда для ссылок в письмах
+	} `yaml:"mail"`
+	Account struct {
+		PasswordResetMinutes      int  `yaml:"password_reset_minutes"`      // сколько действует ссылка сброса пароля
+		EmailVerificationHours    int  `yaml:"email_verification_hours"`    // сколько действует ссылка подтверждения email
+		VerificationResendSeconds int  `yaml:"verification_resend_seconds"` // не чаще одного письма подтверждения за этот период
+		RequireVerifiedEmail      bool `yaml:"require_verified_email"`      // запретить бронирование до подтверждения email
+	} `yaml:"account"`
+	Booking struct {
+		SlotInterval         int `yaml:"slot_interval"`          // шаг сетки слотов, в минутах
+		WaitlistClaimMinutes int `yaml:"waitlist_claim_minutes"` // сколько действует предложение из листа ожидания
+		HoldMinutes          int `yaml:"hold_minutes"`           // сколько держится столик при оформлении бронирования
+		SweepIntervalSeconds int `yaml:"sweep_interval_seconds"` // период фоновых задач
+		DefaultDurations     []DurationRule `yaml:"default_durations"` // длительность, если клиент ее не указал
+	} `yaml:"booking"`
+	NoShow struct {
+		GraceMinutes int    `yaml:"grace_minutes"` // через сколько после начала неявка отмечается автоматически
+		Threshold    int    `yaml:"threshold"`     // с какого числа неявок действует политика
+		Policy       string `yaml:"policy"`        // none, block или deposit
+	} `yaml:"no_show"`
 }
 
 var AppConfig *Config
@@ -42,6 +76,64 @@ func LoadConfig() {
 	}
 
 	overrideWithEnvVars(AppConfig)
+	applyDefaults(AppConfig)
+}
+
+func applyDefaults(config *Config) {
+	if config.JWT.AccessTokenMinutes <= 0 {
+		config.JWT.AccessTokenMinutes = 15
+	}
+	if config.JWT.RefreshTokenDays <= 0 {
+		config.JWT.RefreshTokenDays = 30
+	}
+	if config.Mail.Driver == "" {
+		config.Mail.Driver = "log"
+	}
+	if config.Mail.Dir == "" {
+		config.Mail.Dir = "mail"
+	}
+	if config.Mail.From == "" {
+		config.Mail.From = "noreply@restaurant.local"
+	}
+	if config.Mail.AppURL == "" {
+		config.Mail.AppURL = "http://localhost:3000"
+	}
+	if config.Account.PasswordResetMinutes <= 0 {
+		config.Account.PasswordResetMinutes = 60
+	}
+	if config.Account.EmailVerificationHours <= 0 {
+		config.Account.EmailVerificationHours = 48
+	}
+	if config.Account.VerificationResendSeconds <= 0 {
+		config.Account.VerificationResendSeconds = 60
+	}
+	if config.Booking.SlotInterval <= 0 {
+		config.Booking.SlotInterval = 30
+	}
+	if config.Booking.WaitlistClaimMinutes <= 0 {
+		config.Booking.WaitlistClaimMinutes = 15
+	}
+	if config.Booking.HoldMinutes <= 0 {
+		config.Booking.HoldMinutes = 10
+	}
+	if config.Booking.SweepIntervalSeconds <= 0 {
+		config.Booking.SweepIntervalSeconds = 60
+	}
+	if config.NoShow.GraceMinutes <= 0 {
+		config.NoShow.GraceMinutes = 30
+	}
+	if config.NoShow.Threshold <= 0 {
+		config.NoShow.Threshold = 3
+	}
+	if config.NoShow.Policy == "" {
+		config.NoShow.Policy = "deposit"
+	}
+	switch config.NoShow.Policy {
+	case "none", "block", "deposit":
+	default:
+		log.Printf("Unknown no-show policy %q, using deposit", config.NoShow.Policy)
+		config.NoShow.Policy = "deposit"
+	}
 }
 
 func overrideWithEnvVars(config *Config) {
@@ -72,6 +164,57 @@ func overrideWithEnvVars(config *Config) {
 	if secret := GetEnv("JWT_SECRET", ""); secret != "" {
 		config.JWT.Secret = secret
 	}
+	if minutes := GetEnvInt("JWT_ACCESS_TOKEN_MINUTES", 0); minutes > 0 {
+		config.JWT.AccessTokenMinutes = minutes
+	}
+	if days := GetEnvInt("JWT_REFRESH_TOKEN_DAYS", 0); days > 0 {
+		config.JWT.RefreshTokenDays = days
+	}
+	if driver := GetEnv("MAIL_DRIVER", ""); driver != "" {
+		config.Mail.Driver = driver
+	}
+	if dir := GetEnv("MAIL_DIR", ""); dir != "" {
+		config.Mail.Dir = dir
+	}
+	if from := GetEnv("MAIL_FROM", ""); from != "" {
+		config.Mail.From = from
+	}
+	if appURL := GetEnv("APP_URL", ""); appURL != "" {
+		config.Mail.AppURL = appURL
+	}
+	if minutes := GetEnvInt("PASSWORD_RESET_MINUTES", 0); minutes > 0 {
+		config.Account.PasswordResetMinutes = minutes
+	}
+	if hours := GetEnvInt("EMAIL_VERIFICATION_HOURS", 0); hours > 0 {
+		config.Account.EmailVerificationHours = hours
+	}
+	if seconds := GetEnvInt("VERIFICATION_RESEND_SECONDS", 0); seconds > 0 {
+		config.Account.VerificationResendSeconds = seconds
+	}
+	if require, err := strconv.ParseBool(GetEnv("REQUIRE_VERIFIED_EMAIL", "")); err == nil {
+		config.Account.RequireVerifiedEmail = require
+	}
+	if interval := GetEnvInt("BOOKING_SLOT_INTERVAL", 0); interval > 0 {
+		config.Booking.SlotInterval = interval
+	}
+	if minutes := GetEnvInt("WAITLIST_CLAIM_MINUTES", 0); minutes > 0 {
+		config.Booking.WaitlistClaimMinutes = minutes
+	}
+	if minutes := GetEnvInt("HOLD_MINUTES", 0); minutes > 0 {
+		config.Booking.HoldMinutes = minutes
+	}
+	if seconds := GetEnvInt("SWEEP_INTERVAL_SECONDS", 0); seconds > 0 {
+		config.Booking.SweepIntervalSeconds = seconds
+	}
+	if minutes := GetEnvInt("NO_SHOW_GRACE_MINUTES", 0); minutes > 0 {
+		config.NoShow.GraceMinutes = minutes
+	}
+	if threshold := GetEnvInt("NO_SHOW_THRESHOLD", 0); threshold > 0 {
+		config.NoShow.Threshold = threshold
+	}
+	if policy := GetEnv("NO_SHOW_POLICY", ""); policy != "" {
+		config.NoShow.Policy = policy
+	}
 }
 
 func GetEnv(key string, defaultValue string) string {
@@ -82,6 +225,14 @@ func GetEnv(key string, defaultValue string) string {
 	return value
 }
 
+func GetEnvInt(key string, defaultValue int) int {
+	value, err := strconv.Atoi(GetEnv(key, ""))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
 func LoadYAMLConfig(filename string) (*Config, error) {
 	data, err := os.ReadFile(filename)
 	if err != nil {
@@ -95,4 +246,15 @@ func LoadYAMLConfig(filename string) (*Config, error) {
 	}
 
 	return &config, nil
-} 
\ No newline at end of file
+}
+
+// Длительность бронирования по умолчанию для компании из guests человек
+// по первому подходящему правилу; 0, если правила не заданы
+func (c *Config) DefaultDuration(guests int) int {
+	for _, rule := range c.Booking.DefaultDurations {
+		if rule.Minutes > 0 && (rule.MaxGuests == 0 || guests <= rule.MaxGuests) {
+			return rule.Minutes
+		}
+	}
+	return 0
+}
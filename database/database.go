@@ -6,6 +6,7 @@ import (
 	"restaurant-booking/config"
 	"restaurant-booking/models"
 	"restaurant-booking/utils"
+	"time"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -39,20 +40,100 @@ func ConnectDB() {
 }
 
 func AutoMigrate() {
+	// Пользователи, зарегистрированные до подтверждения email, считаются подтвердившими
+	verifyExistingUsers := !DB.Migrator().HasColumn(&models.User{}, "email_verified_at")
+
 	err := DB.AutoMigrate(
 		&models.User{},
 		&models.Restaurant{},
 		&models.Table{},
+		&models.BookingSeries{},
 		&models.Booking{},
+		&models.OpeningHours{},
+		&models.DateException{},
+		&models.TableCombination{},
+		&models.WaitlistEntry{},
+		&models.SlotHold{},
+		&models.RefreshToken{},
+		&models.PasswordResetToken{},
+		&models.EmailVerificationToken{},
 	)
 	
 	if err != nil {
 		log.Fatal("Failed to migrate database:", err)
 	}
+
+	if verifyExistingUsers {
+		markExistingUsersVerified()
+	}
+	backfillBookingWindows()
+	backfillBookingTables()
+	resetLegacyTableStatuses()
+	createTableNumberIndex()
 	
 	log.Println("Database migrated successfully")
 }
 
+// Связать бронирования, созданные до появления booking_tables, с их столиком
+func backfillBookingTables() {
+	if err := DB.Exec(`INSERT INTO booking_tables (booking_id, table_id)
+		SELECT b.id, b.table_id FROM bookings b
+		WHERE NOT EXISTS (SELECT 1 FROM booking_tables bt WHERE bt.booking_id = b.id)`).Error; err != nil {
+		log.Printf("Error backfilling booking tables: %v", err)
+	}
+}
+
+// Отметить email всех существующих пользователей подтвержденным.
+// Выполняется один раз, при появлении колонки email_verified_at.
+func markExistingUsersVerified() {
+	if err := DB.Model(&models.User{}).Where("email_verified_at IS NULL").
+		Update("email_verified_at", gorm.Expr("created_at")).Error; err != nil {
+		log.Printf("Error marking existing users verified: %v", err)
+	}
+}
+
+// Статус "booked" больше не используется: занятость считается по бронированиям
+func resetLegacyTableStatuses() {
+	if err := DB.Model(&models.Table{}).Where("status = ?", "booked").
+		Update("status", models.TableStatusAvailable).Error; err != nil {
+		log.Printf("Error resetting legacy table statuses: %v", err)
+	}
+}
+
+// Номер столика уникален в ресторане среди неудаленных столиков,
+// поэтому номер удаленного столика можно использовать снова
+func createTableNumberIndex() {
+	if err := DB.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_tables_restaurant_number
+		ON tables (restaurant_id, number) WHERE deleted_at IS NULL`).Error; err != nil {
+		log.Printf("Error creating table number index: %v", err)
+	}
+}
+
+// Заполнить starts_at/ends_at для бронирований, созданных до их появления
+func backfillBookingWindows() {
+	var bookings []models.Booking
+	// Бронирования с нулевой длительностью создавались, когда клиент не передавал duration
+	if err := DB.Where("starts_at IS NULL OR ends_at IS NULL OR duration <= 0").Find(&bookings).Error; err != nil {
+		log.Printf("Error loading bookings for backfill: %v", err)
+		return
+	}
+
+	for _, booking := range bookings {
+		if booking.Duration <= 0 {
+			booking.Duration = models.DefaultBookingDuration
+		}
+		if err := booking.SetWindow(); err != nil {
+			log.Printf("Skipping booking %d backfill: %v", booking.ID, err)
+			continue
+		}
+		DB.Model(&booking).Updates(map[string]interface{}{
+			"duration":  booking.Duration,
+			"starts_at": booking.StartsAt,
+			"ends_at":   booking.EndsAt,
+		})
+	}
+}
+
 func SeedData() {
 	var userCount int64
 	DB.Model(&models.User{}).Count(&userCount)
@@ -60,6 +141,7 @@ func SeedData() {
 	
 	if userCount == 0 {
 		hashedPassword, _ := utils.HashPassword("password123")
+		verifiedAt := time.Now()
 		
 		adminUser := models.User{
 			Username:  "admin",
@@ -68,6 +150,7 @@ func SeedData() {
 			FirstName: "Admin",
 			LastName:  "User",
 			Role:      "admin",
+			EmailVerifiedAt: &verifiedAt,
 		}
 		if err := DB.Create(&adminUser).Error; err != nil {
 			log.Printf("Error creating admin user: %v", err)
@@ -82,6 +165,7 @@ func SeedData() {
 			FirstName: "Итальянский",
 			LastName:  "Админ",
 			Role:      "restaurant_admin",
+			EmailVerifiedAt: &verifiedAt,
 		}
 		if err := DB.Create(&italianAdmin).Error; err != nil {
 			log.Printf("Error creating italian admin user: %v", err)
@@ -96,6 +180,7 @@ func SeedData() {
 			FirstName: "Сакура",
 			LastName:  "Админ",
 			Role:      "restaurant_admin",
+			EmailVerifiedAt: &verifiedAt,
 		}
 		if err := DB.Create(&sakuraAdmin).Error; err != nil {
 			log.Printf("Error creating sakura admin user: %v", err)
@@ -138,12 +223,19 @@ func SeedData() {
 				{RestaurantID: restaurant.ID, Number: 1, Capacity: 2, Status: "available", Location: "У окна"},
 				{RestaurantID: restaurant.ID, Number: 2, Capacity: 4, Status: "available", Location: "В центре"},
 				{RestaurantID: restaurant.ID, Number: 3, Capacity: 6, Status: "available", Location: "У стены"},
-				{RestaurantID: restaurant.ID, Number: 4, Capacity: 8, Status: "available", Location: "VIP зона"},
+				{RestaurantID: restaurant.ID, Number: 4, Capacity: 8, MinGuests: 4, Status: "available", Location: "VIP зона"},
 				{RestaurantID: restaurant.ID, Number: 5, Capacity: 2, Status: "available", Location: "Терраса"},
 			}
 			DB.Create(&tables)
 			log.Printf("Created %d tables for restaurant: %s", len(tables), restaurant.Name)
 
+			combinations := []models.TableCombination{
+				{RestaurantID: restaurant.ID, Name: "Центр и стена", Capacity: 10, MinGuests: 5, Tables: []models.Table{tables[1], tables[2]}},
+				{RestaurantID: restaurant.ID, Name: "Стена и VIP зона", Capacity: 14, MinGuests: 8, Tables: []models.Table{tables[2], tables[3]}},
+			}
+			DB.Create(&combinations)
+			log.Printf("Created %d table combinations for restaurant: %s", len(combinations), restaurant.Name)
+
 			if i == 0 {
 				var italianAdmin models.User
 				if err := DB.Where("username = ?", "italian_admin").First(&italianAdmin).Error; err != nil {
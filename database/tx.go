@@ -0,0 +1,36 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// Сколько раз повторять транзакцию, прерванную конфликтом сериализации
+const maxSerializableRetries = 3
+
+// Выполнить fn в транзакции с уровнем изоляции SERIALIZABLE.
+// PostgreSQL прерывает одну из конкурирующих транзакций, которые прочитали
+// и изменили пересекающиеся данные, поэтому проверка "столик свободен" и
+// вставка бронирования выполняются атомарно. Прерванная транзакция повторяется.
+func WithSerializableTx(fn func(tx *gorm.DB) error) error {
+	var err error
+	for attempt := 0; attempt < maxSerializableRetries; attempt++ {
+		err = DB.Transaction(fn, &sql.TxOptions{Isolation: sql.LevelSerializable})
+		if !IsSerializationFailure(err) {
+			return err
+		}
+	}
+	return err
+}
+
+// Является ли ошибка конфликтом сериализации или взаимной блокировкой
+func IsSerializationFailure(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == "40001" || pgErr.Code == "40P01"
+	}
+	return false
+}
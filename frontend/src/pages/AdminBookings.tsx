@@ -123,8 +123,12 @@ const AdminBookings: React.FC = () => {
         return 'success'
       case 'cancelled':
         return 'error'
+      case 'seated':
+        return 'primary'
       case 'completed':
         return 'info'
+      case 'no_show':
+        return 'error'
       default:
         return 'default'
     }
@@ -138,8 +142,12 @@ const AdminBookings: React.FC = () => {
         return 'Подтверждено'
       case 'cancelled':
         return 'Отменено'
+      case 'seated':
+        return 'Гости за столом'
       case 'completed':
         return 'Завершено'
+      case 'no_show':
+        return 'Неявка'
       default:
         return status
     }
@@ -249,10 +257,10 @@ const AdminBookings: React.FC = () => {
                         variant="outlined"
                         color="info"
                         startIcon={<Edit />}
-                        onClick={() => handleUpdateStatus({ ...booking, status: 'completed' })}
+                        onClick={() => handleUpdateStatus({ ...booking, status: 'seated' })}
                         sx={{ flex: 1 }}
                       >
-                        Завершить
+                        Гости пришли
                       </Button>
                       <Button
                         size="small"
@@ -266,15 +274,16 @@ const AdminBookings: React.FC = () => {
                       </Button>
                     </Box>
                   )}
-                  {(booking.status === 'cancelled' || booking.status === 'completed') && (
+                  {booking.status === 'seated' && (
                     <Button
                       size="small"
                       variant="outlined"
-                      startIcon={<Edit />}
-                      onClick={() => handleUpdateStatus(booking)}
+                      color="info"
+                      startIcon={<CheckCircle />}
+                      onClick={() => handleUpdateStatus({ ...booking, status: 'completed' })}
                       fullWidth
                     >
-                      Изменить статус
+                      Завершить
                     </Button>
                   )}
                 </Box>
@@ -296,8 +305,10 @@ const AdminBookings: React.FC = () => {
             >
               <MenuItem value="pending">Ожидает подтверждения</MenuItem>
               <MenuItem value="confirmed">Подтверждено</MenuItem>
+              <MenuItem value="seated">Гости за столом</MenuItem>
               <MenuItem value="cancelled">Отменено</MenuItem>
               <MenuItem value="completed">Завершено</MenuItem>
+              <MenuItem value="no_show">Неявка</MenuItem>
             </Select>
           </FormControl>
         </DialogContent>
@@ -23,7 +23,7 @@ import { AdapterDateFns } from '@mui/x-date-pickers/AdapterDateFns'
 import { ru } from 'date-fns/locale'
 import { useParams, useNavigate, useSearchParams } from 'react-router-dom'
 import { useQuery, useMutation, useQueryClient } from 'react-query'
-import { restaurantAPI, bookingAPI } from '../services/api'
+import { restaurantAPI, bookingAPI, holdAPI } from '../services/api'
 import { useAuth } from '../contexts/AuthContext'
 import { format } from 'date-fns'
 
@@ -40,6 +40,7 @@ const BookingForm: React.FC = () => {
     notes: '',
   })
   const [selectedTable, setSelectedTable] = useState<number | null>(null)
+  const [holdToken, setHoldToken] = useState<string | null>(null)
   const [loading, setLoading] = useState(false)
   const [error, setError] = useState('')
   const [isEditing, setIsEditing] = useState(false)
@@ -120,6 +121,37 @@ const BookingForm: React.FC = () => {
     }
   )
 
+  // Удерживаем выбранный столик, пока пользователь заполняет форму
+  const selectTable = async (tableId: number | null) => {
+    // Новое удержание на то же время разрешено только после снятия прежнего
+    if (holdToken) {
+      await holdAPI.release(holdToken).catch(() => undefined)
+      setHoldToken(null)
+    }
+    setSelectedTable(tableId)
+    if (tableId === null || isEditing) {
+      return
+    }
+
+    try {
+      const hold = await holdAPI.create({
+        table_id: tableId,
+        restaurant_id: Number(restaurantId),
+        date: format(formData.date, 'yyyy-MM-dd'),
+        time: format(formData.time, 'HH:mm'),
+        duration: 120,
+        guests: formData.guests,
+        notes: formData.notes,
+      })
+      setHoldToken(hold.token)
+      setError('')
+    } catch (err: any) {
+      setSelectedTable(null)
+      setError(err.response?.data?.error || 'Не удалось удержать столик')
+      refetchTables()
+    }
+  }
+
   const handleSubmit = async (e: React.FormEvent) => {
     e.preventDefault()
     if (!selectedTable) {
@@ -138,6 +170,7 @@ const BookingForm: React.FC = () => {
       duration: 120,
       guests: formData.guests,
       notes: formData.notes,
+      hold_token: holdToken || undefined,
     }
 
     try {
@@ -185,7 +218,7 @@ const BookingForm: React.FC = () => {
                         if (newValue) {
                           setFormData({ ...formData, date: newValue })
                           if (!isEditing) {
-                            setSelectedTable(null)
+                            selectTable(null)
                           }
                         }
                       }}
@@ -201,7 +234,7 @@ const BookingForm: React.FC = () => {
                         if (newValue) {
                           setFormData({ ...formData, time: newValue })
                           if (!isEditing) {
-                            setSelectedTable(null)
+                            selectTable(null)
                           }
                         }
                       }}
@@ -217,7 +250,7 @@ const BookingForm: React.FC = () => {
                         onChange={(e) => {
                           setFormData({ ...formData, guests: e.target.value as number })
                           if (!isEditing) {
-                            setSelectedTable(null)
+                            selectTable(null)
                           }
                         }}
                       >
@@ -279,7 +312,7 @@ const BookingForm: React.FC = () => {
                           transform: 'translateY(-2px)',
                         },
                       }}
-                      onClick={() => setSelectedTable(table.id)}
+                      onClick={() => selectTable(table.id)}
                     >
                       <CardContent>
                         <Typography variant="h6" gutterBottom>
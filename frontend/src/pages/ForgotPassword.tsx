@@ -0,0 +1,116 @@
+import React, { useState } from 'react'
+import {
+  Box,
+  Paper,
+  TextField,
+  Button,
+  Typography,
+  Link,
+  Alert,
+  CircularProgress,
+} from '@mui/material'
+import { useNavigate } from 'react-router-dom'
+import { authAPI } from '../services/api'
+
+const ForgotPassword: React.FC = () => {
+  const navigate = useNavigate()
+  const [email, setEmail] = useState('')
+  const [sent, setSent] = useState(false)
+  const [error, setError] = useState('')
+  const [loading, setLoading] = useState(false)
+
+  const handleSubmit = async (e: React.FormEvent) => {
+    e.preventDefault()
+    setError('')
+    setLoading(true)
+
+    try {
+      await authAPI.requestPasswordReset(email)
+      setSent(true)
+    } catch (err: any) {
+      setError(err.response?.data?.error || 'Не удалось отправить письмо')
+    } finally {
+      setLoading(false)
+    }
+  }
+
+  return (
+    <Box
+      sx={{
+        display: 'flex',
+        justifyContent: 'center',
+        alignItems: 'center',
+        minHeight: '60vh',
+      }}
+    >
+      <Paper
+        elevation={3}
+        sx={{
+          p: 4,
+          width: '100%',
+          maxWidth: 400,
+          borderRadius: 3,
+        }}
+      >
+        <Typography variant="h4" component="h1" gutterBottom align="center" sx={{ fontWeight: 600 }}>
+          Сброс пароля
+        </Typography>
+        <Typography variant="body1" color="text.secondary" align="center" sx={{ mb: 4 }}>
+          Укажите email, и мы отправим ссылку для создания нового пароля
+        </Typography>
+
+        {error && (
+          <Alert severity="error" sx={{ mb: 3 }}>
+            {error}
+          </Alert>
+        )}
+
+        {sent ? (
+          <Alert severity="success" sx={{ mb: 3 }}>
+            Если адрес зарегистрирован, письмо со ссылкой уже отправлено
+          </Alert>
+        ) : (
+          <Box component="form" onSubmit={handleSubmit} sx={{ mt: 1 }}>
+            <TextField
+              margin="normal"
+              required
+              fullWidth
+              id="email"
+              label="Email"
+              name="email"
+              type="email"
+              autoComplete="email"
+              autoFocus
+              value={email}
+              onChange={(e) => setEmail(e.target.value)}
+              sx={{ mb: 3 }}
+            />
+            <Button
+              type="submit"
+              fullWidth
+              variant="contained"
+              size="large"
+              disabled={loading}
+              sx={{ py: 1.5, fontSize: '1.1rem' }}
+            >
+              {loading ? <CircularProgress size={24} /> : 'Отправить ссылку'}
+            </Button>
+          </Box>
+        )}
+
+        <Box sx={{ mt: 3, textAlign: 'center' }}>
+          <Link
+            component="button"
+            variant="body2"
+            onClick={() => navigate('/login')}
+            sx={{ textDecoration: 'none' }}
+          >
+            Вернуться ко входу
+          </Link>
+        </Box>
+      </Paper>
+    </Box>
+  )
+}
+
+export default ForgotPassword
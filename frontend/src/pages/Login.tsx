@@ -37,7 +37,7 @@ const Login: React.FC = () => {
 
     try {
       const response = await authAPI.login(formData)
-      login(response.token, response.user)
+      login(response.token, response.user, response.refresh_token)
       navigate('/')
     } catch (err: any) {
       setError(err.response?.data?.error || 'Ошибка входа')
@@ -115,6 +115,15 @@ const Login: React.FC = () => {
             {loading ? <CircularProgress size={24} /> : 'Войти'}
           </Button>
           <Box sx={{ mt: 3, textAlign: 'center' }}>
+            <Link
+              component="button"
+              type="button"
+              variant="body2"
+              onClick={() => navigate('/forgot-password')}
+              sx={{ textDecoration: 'none', mb: 1 }}
+            >
+              Забыли пароль?
+            </Link>
             <Typography variant="body2" color="text.secondary">
               Нет аккаунта?{' '}
               <Link
@@ -107,8 +107,12 @@ const MyBookings: React.FC = () => {
         return 'success'
       case 'cancelled':
         return 'error'
+      case 'seated':
+        return 'primary'
       case 'completed':
         return 'info'
+      case 'no_show':
+        return 'error'
       default:
         return 'default'
     }
@@ -122,8 +126,12 @@ const MyBookings: React.FC = () => {
         return 'Подтверждено'
       case 'cancelled':
         return 'Отменено'
+      case 'seated':
+        return 'Гости за столом'
       case 'completed':
         return 'Завершено'
+      case 'no_show':
+        return 'Неявка'
       default:
         return status
     }
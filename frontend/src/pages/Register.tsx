@@ -50,7 +50,7 @@ const Register: React.FC = () => {
     try {
       const { confirmPassword, ...registerData } = formData
       const response = await authAPI.register(registerData)
-      login(response.token, response.user)
+      login(response.token, response.user, response.refresh_token)
       navigate('/')
     } catch (err: any) {
       setError(err.response?.data?.error || 'Ошибка регистрации')
@@ -0,0 +1,129 @@
+import React, { useState } from 'react'
+import {
+  Box,
+  Paper,
+  TextField,
+  Button,
+  Typography,
+  Alert,
+  CircularProgress,
+} from '@mui/material'
+import { useNavigate, useSearchParams } from 'react-router-dom'
+import { authAPI } from '../services/api'
+
+const ResetPassword: React.FC = () => {
+  const navigate = useNavigate()
+  const [searchParams] = useSearchParams()
+  const token = searchParams.get('token') || ''
+  const [formData, setFormData] = useState({
+    password: '',
+    confirmPassword: '',
+  })
+  const [error, setError] = useState('')
+  const [loading, setLoading] = useState(false)
+
+  const handleChange = (e: React.ChangeEvent<HTMLInputElement>) => {
+    setFormData({
+      ...formData,
+      [e.target.name]: e.target.value,
+    })
+  }
+
+  const handleSubmit = async (e: React.FormEvent) => {
+    e.preventDefault()
+    setError('')
+
+    if (formData.password !== formData.confirmPassword) {
+      setError('Пароли не совпадают')
+      return
+    }
+
+    setLoading(true)
+    try {
+      await authAPI.confirmPasswordReset(token, formData.password)
+      navigate('/login')
+    } catch (err: any) {
+      setError(err.response?.data?.error || 'Не удалось сменить пароль')
+    } finally {
+      setLoading(false)
+    }
+  }
+
+  return (
+    <Box
+      sx={{
+        display: 'flex',
+        justifyContent: 'center',
+        alignItems: 'center',
+        minHeight: '60vh',
+      }}
+    >
+      <Paper
+        elevation={3}
+        sx={{
+          p: 4,
+          width: '100%',
+          maxWidth: 400,
+          borderRadius: 3,
+        }}
+      >
+        <Typography variant="h4" component="h1" gutterBottom align="center" sx={{ fontWeight: 600 }}>
+          Новый пароль
+        </Typography>
+
+        {!token && (
+          <Alert severity="warning" sx={{ mb: 3 }}>
+            Ссылка для сброса пароля недействительна
+          </Alert>
+        )}
+
+        {error && (
+          <Alert severity="error" sx={{ mb: 3 }}>
+            {error}
+          </Alert>
+        )}
+
+        <Box component="form" onSubmit={handleSubmit} sx={{ mt: 1 }}>
+          <TextField
+            margin="normal"
+            required
+            fullWidth
+            name="password"
+            label="Новый пароль"
+            type="password"
+            id="password"
+            autoComplete="new-password"
+            value={formData.password}
+            onChange={handleChange}
+            sx={{ mb: 2 }}
+          />
+          <TextField
+            margin="normal"
+            required
+            fullWidth
+            name="confirmPassword"
+            label="Повторите пароль"
+            type="password"
+            id="confirmPassword"
+            autoComplete="new-password"
+            value={formData.confirmPassword}
+            onChange={handleChange}
+            sx={{ mb: 3 }}
+          />
+          <Button
+            type="submit"
+            fullWidth
+            variant="contained"
+            size="large"
+            disabled={loading || !token}
+            sx={{ py: 1.5, fontSize: '1.1rem' }}
+          >
+            {loading ? <CircularProgress size={24} /> : 'Сохранить пароль'}
+          </Button>
+        </Box>
+      </Paper>
+    </Box>
+  )
+}
+
+export default ResetPassword
@@ -0,0 +1,124 @@
+import React, { useEffect, useState } from 'react'
+import {
+  Box,
+  Paper,
+  Button,
+  Typography,
+  Alert,
+  CircularProgress,
+} from '@mui/material'
+import { useNavigate, useSearchParams } from 'react-router-dom'
+import { useAuth } from '../contexts/AuthContext'
+import { authAPI } from '../services/api'
+
+const VerifyEmail: React.FC = () => {
+  const navigate = useNavigate()
+  const [searchParams] = useSearchParams()
+  const token = searchParams.get('token') || ''
+  const { user, token: accessToken, login, isAuthenticated } = useAuth()
+  const [status, setStatus] = useState<'pending' | 'verified' | 'failed'>(token ? 'pending' : 'failed')
+  const [error, setError] = useState(token ? '' : 'Ссылка подтверждения недействительна')
+  const [message, setMessage] = useState('')
+  const [sending, setSending] = useState(false)
+
+  useEffect(() => {
+    if (!token) {
+      return
+    }
+    authAPI
+      .verifyEmail(token)
+      .then(() => setStatus('verified'))
+      .catch((err: any) => {
+        setStatus('failed')
+        setError(err.response?.data?.error || 'Не удалось подтвердить email')
+      })
+  }, [token])
+
+  // Обновляем сохраненного пользователя, чтобы не ждать следующего входа
+  useEffect(() => {
+    if (status === 'verified' && user && accessToken && !user.email_verified) {
+      login(accessToken, { ...user, email_verified: true })
+    }
+  }, [status, user, accessToken, login])
+
+  const handleResend = async () => {
+    setSending(true)
+    setMessage('')
+    try {
+      await authAPI.resendVerification()
+      setMessage('Письмо отправлено повторно')
+      setError('')
+    } catch (err: any) {
+      setError(err.response?.data?.error || 'Не удалось отправить письмо')
+    } finally {
+      setSending(false)
+    }
+  }
+
+  return (
+    <Box
+      sx={{
+        display: 'flex',
+        justifyContent: 'center',
+        alignItems: 'center',
+        minHeight: '60vh',
+      }}
+    >
+      <Paper
+        elevation={3}
+        sx={{
+          p: 4,
+          width: '100%',
+          maxWidth: 400,
+          borderRadius: 3,
+        }}
+      >
+        <Typography variant="h4" component="h1" gutterBottom align="center" sx={{ fontWeight: 600 }}>
+          Подтверждение email
+        </Typography>
+
+        {status === 'pending' && (
+          <Box sx={{ display: 'flex', justifyContent: 'center', my: 3 }}>
+            <CircularProgress />
+          </Box>
+        )}
+
+        {status === 'verified' && (
+          <Alert severity="success" sx={{ mb: 3 }}>
+            Email подтвержден, теперь можно бронировать столики
+          </Alert>
+        )}
+
+        {error && (
+          <Alert severity="error" sx={{ mb: 3 }}>
+            {error}
+          </Alert>
+        )}
+
+        {message && (
+          <Alert severity="info" sx={{ mb: 3 }}>
+            {message}
+          </Alert>
+        )}
+
+        {status === 'failed' && isAuthenticated && (
+          <Button
+            fullWidth
+            variant="outlined"
+            disabled={sending}
+            onClick={handleResend}
+            sx={{ mb: 2 }}
+          >
+            {sending ? <CircularProgress size={24} /> : 'Отправить письмо еще раз'}
+          </Button>
+        )}
+
+        <Button fullWidth variant="contained" onClick={() => navigate('/restaurants')}>
+          К ресторанам
+        </Button>
+      </Paper>
+    </Box>
+  )
+}
+
+export default VerifyEmail
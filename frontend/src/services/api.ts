@@ -1,5 +1,5 @@
 import axios from 'axios'
-import { LoginRequest, RegisterRequest, CreateBookingRequest, Restaurant, Booking, Table } from '../types'
+import { LoginRequest, RegisterRequest, CreateBookingRequest, CreateBookingSeriesRequest, UpdateBookingRequest, JoinWaitlistRequest, Restaurant, Booking, BookingSeries, OccurrenceConflict, Table, Slot, SlotHold, WaitlistEntry } from '../types'
 
 const API_BASE_URL = '/api'
 
@@ -18,11 +18,42 @@ api.interceptors.request.use((config) => {
   return config
 })
 
+// Одновременные 401 ждут один и тот же обмен refresh-токена
+let refreshRequest: Promise<string> | null = null
+
+const refreshAccessToken = async (): Promise<string> => {
+  const refreshToken = localStorage.getItem('refresh_token')
+  if (!refreshToken) {
+    throw new Error('no refresh token')
+  }
+  const response = await axios.post(`${API_BASE_URL}/token/refresh`, { refresh_token: refreshToken })
+  localStorage.setItem('token', response.data.token)
+  localStorage.setItem('refresh_token', response.data.refresh_token)
+  localStorage.setItem('user', JSON.stringify(response.data.user))
+  return response.data.token
+}
+
 api.interceptors.response.use(
   (response) => response,
-  (error) => {
-    if (error.response?.status === 401) {
+  async (error) => {
+    const original = error.config
+    if (error.response?.status === 401 && original && !original._retry) {
+      original._retry = true
+      try {
+        refreshRequest = refreshRequest || refreshAccessToken()
+        const token = await refreshRequest
+        original.headers.Authorization = `Bearer ${token}`
+        return api(original)
+      } catch {
+        localStorage.removeItem('token')
+        localStorage.removeItem('refresh_token')
+        window.location.href = '/login'
+      } finally {
+        refreshRequest = null
+      }
+    } else if (error.response?.status === 401) {
       localStorage.removeItem('token')
+      localStorage.removeItem('refresh_token')
       window.location.href = '/login'
     }
     return Promise.reject(error)
@@ -38,6 +69,21 @@ export const authAPI = {
     const response = await api.post('/register', userData)
     return response.data
   },
+  logout: async (refreshToken: string) => {
+    await api.post('/logout', { refresh_token: refreshToken })
+  },
+  requestPasswordReset: async (email: string) => {
+    await api.post('/password/reset', { email })
+  },
+  confirmPasswordReset: async (token: string, password: string) => {
+    await api.post('/password/reset/confirm', { token, password })
+  },
+  verifyEmail: async (token: string) => {
+    await api.post('/email/verify', { token })
+  },
+  resendVerification: async () => {
+    await api.post('/email/verify/resend')
+  },
 }
 
 export const restaurantAPI = {
@@ -55,6 +101,12 @@ export const restaurantAPI = {
     })
     return response.data.tables
   },
+  getSlots: async (restaurantId: number, date: string, guests: number, duration?: number): Promise<Slot[]> => {
+    const response = await api.get(`/restaurants/${restaurantId}/slots`, {
+      params: { date, guests, duration },
+    })
+    return response.data.slots
+  },
 }
 
 export const bookingAPI = {
@@ -70,7 +122,7 @@ export const bookingAPI = {
     const response = await api.post('/bookings', bookingData)
     return response.data.booking
   },
-  update: async (id: number, bookingData: Partial<CreateBookingRequest>): Promise<Booking> => {
+  update: async (id: number, bookingData: UpdateBookingRequest): Promise<Booking> => {
     const response = await api.put(`/bookings/id/${id}`, bookingData)
     return response.data.booking
   },
@@ -79,4 +131,46 @@ export const bookingAPI = {
   },
 }
 
+export const seriesAPI = {
+  getMine: async (): Promise<BookingSeries[]> => {
+    const response = await api.get('/bookings/series')
+    return response.data.series
+  },
+  create: async (request: CreateBookingSeriesRequest): Promise<{ series: BookingSeries; bookings: Booking[]; conflicts: OccurrenceConflict[] }> => {
+    const response = await api.post('/bookings/series', request)
+    return response.data
+  },
+  cancel: async (id: number): Promise<void> => {
+    await api.delete(`/bookings/series/${id}`)
+  },
+}
+
+export const holdAPI = {
+  create: async (request: CreateBookingRequest): Promise<SlotHold> => {
+    const response = await api.post('/holds', request)
+    return response.data.hold
+  },
+  release: async (token: string): Promise<void> => {
+    await api.delete(`/holds/${token}`)
+  },
+}
+
+export const waitlistAPI = {
+  getMine: async (): Promise<WaitlistEntry[]> => {
+    const response = await api.get('/waitlist')
+    return response.data.waitlist
+  },
+  join: async (request: JoinWaitlistRequest): Promise<WaitlistEntry> => {
+    const response = await api.post('/waitlist', request)
+    return response.data.entry
+  },
+  leave: async (id: number): Promise<void> => {
+    await api.delete(`/waitlist/id/${id}`)
+  },
+  claim: async (id: number): Promise<Booking> => {
+    const response = await api.post(`/waitlist/id/${id}/claim`)
+    return response.data.booking
+  },
+}
+
 export default api 
\ No newline at end of file
@@ -7,6 +7,9 @@ export interface User {
   phone: string
   role: string
   restaurant_id?: number
+  no_show_count?: number
+  email_verified?: boolean
+  email_verified_at?: string
   created_at: string
   updated_at: string
   restaurant?: Restaurant
@@ -22,9 +25,39 @@ export interface Restaurant {
   website: string
   opening_time: string
   closing_time: string
+  assignment_strategy: string
+  min_party_size: number
+  max_party_size: number
+  free_cancellation_hours: number
+  cancellation_cutoff_minutes: number
+  min_lead_minutes: number
+  max_advance_days: number
+  turnover_buffer_minutes: number
   created_at: string
   updated_at: string
   tables?: Table[]
+  date_exceptions?: DateException[]
+  table_combinations?: TableCombination[]
+}
+
+export interface TableCombination {
+  id: number
+  restaurant_id: number
+  name: string
+  capacity: number
+  min_guests: number
+  tables?: Table[]
+}
+
+export interface DateException {
+  id: number
+  restaurant_id: number
+  date: string
+  type: 'closed' | 'hours' | 'tables'
+  opens_at?: string
+  closes_at?: string
+  reason: string
+  tables?: Table[]
 }
 
 export interface Table {
@@ -32,14 +65,18 @@ export interface Table {
   restaurant_id: number
   number: number
   capacity: number
+  min_guests: number
   status: string
   location: string
+  turnover_buffer_minutes?: number
   created_at: string
   updated_at: string
   restaurant?: Restaurant
   bookings?: Booking[]
 }
 
+export type BookingStatus = 'pending' | 'confirmed' | 'seated' | 'completed' | 'cancelled' | 'no_show'
+
 export interface Booking {
   id: number
   user_id: number
@@ -49,12 +86,72 @@ export interface Booking {
   time: string
   duration: number
   guests: number
-  status: string
+  status: BookingStatus
   notes: string
   created_at: string
   updated_at: string
   user?: User
   table?: Table
+  tables?: Table[]
+  table_combination_id?: number
+  series_id?: number
+  deposit_required?: boolean
+  late_cancellation?: boolean
+  cancelled_at?: string
+  restaurant?: Restaurant
+}
+
+export interface BookingSeries {
+  id: number
+  user_id: number
+  restaurant_id: number
+  table_id: number
+  table_combination_id?: number
+  frequency: 'weekly' | 'monthly'
+  interval: number
+  start_date: string
+  until?: string
+  count?: number
+  time: string
+  duration: number
+  guests: number
+  notes: string
+  status: 'active' | 'cancelled'
+  bookings?: Booking[]
+  restaurant?: Restaurant
+}
+
+export interface OccurrenceConflict {
+  date: string
+  reason: string
+}
+
+export interface Slot {
+  date: string
+  time: string
+  available: number
+  tables: Table[]
+}
+
+export type WaitlistStatus = 'waiting' | 'offered' | 'claimed' | 'expired' | 'cancelled'
+
+export interface WaitlistEntry {
+  id: number
+  user_id: number
+  restaurant_id: number
+  date: string
+  time_from: string
+  time_to: string
+  duration: number
+  guests: number
+  notes: string
+  status: WaitlistStatus
+  offered_booking_id?: number
+  offered_time?: string
+  offer_expires_at?: string
+  booking_id?: number
+  created_at: string
+  updated_at: string
   restaurant?: Restaurant
 }
 
@@ -73,11 +170,65 @@ export interface RegisterRequest {
 }
 
 export interface CreateBookingRequest {
-  table_id: number
+  table_id?: number
   restaurant_id: number
   date: string
   time: string
   duration: number
   guests: number
   notes: string
-} 
\ No newline at end of file
+  location_preference?: string
+  combination_id?: number
+  hold_token?: string
+}
+
+export interface SlotHold {
+  id: number
+  token: string
+  user_id: number
+  restaurant_id: number
+  table_id: number
+  table_combination_id?: number
+  date: string
+  time: string
+  duration: number
+  guests: number
+  starts_at: string
+  ends_at: string
+  expires_at: string
+  tables?: Table[]
+}
+
+export interface CreateBookingSeriesRequest {
+  restaurant_id: number
+  table_id?: number
+  combination_id?: number
+  date: string
+  time: string
+  duration?: number
+  guests: number
+  notes?: string
+  frequency: 'weekly' | 'monthly'
+  interval?: number
+  count?: number
+  until?: string
+  skip_conflicts?: boolean
+}
+
+export interface JoinWaitlistRequest {
+  restaurant_id: number
+  date: string
+  time_from: string
+  time_to: string
+  duration?: number
+  guests: number
+  notes?: string
+}
+
+export interface UpdateBookingRequest {
+  date?: string
+  time?: string
+  duration?: number
+  guests?: number
+  notes?: string
+}
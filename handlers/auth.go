@@ -1,10 +1,15 @@
 package handlers
 
 import (
+	"errors"
+	"log"
 	"net/http"
+	"restaurant-booking/config"
 	"restaurant-booking/database"
 	"restaurant-booking/models"
+	"restaurant-booking/services"
 	"restaurant-booking/utils"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -49,7 +54,7 @@ func Register(c *gin.Context) {
 		FirstName: req.FirstName,
 		LastName:  req.LastName,
 		Phone:     req.Phone,
-		Role:      "customer",
+		Role:      models.RoleCustomer,
 	}
 
 	if err := database.DB.Create(&user).Error; err != nil {
@@ -57,23 +62,22 @@ func Register(c *gin.Context) {
 		return
 	}
 
-	token, err := utils.GenerateToken(user.ID, user.Username)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+	// Ошибка отправки не мешает регистрации: письмо можно запросить повторно
+	if _, err := sendEmailVerification(user); err != nil {
+		log.Printf("email verification: failed to send to new user %d: %v", user.ID, err)
+	}
+
+	token, refreshToken, ok := issueTokenPair(c, user, "")
+	if !ok {
 		return
 	}
 
 	c.JSON(http.StatusCreated, gin.H{
-		"message": "User registered successfully",
-		"token":   token,
-		"user": gin.H{
-			"id":        user.ID,
-			"username":  user.Username,
-			"email":     user.Email,
-			"first_name": user.FirstName,
-			"last_name":  user.LastName,
-			"role":      user.Role,
-		},
+		"message":       "User registered successfully",
+		"token":         token,
+		"refresh_token": refreshToken,
+		"expires_in":    int(utils.AccessTokenTTL().Seconds()),
+		"user":          authUser(user),
 	})
 }
 
@@ -95,22 +99,128 @@ func Login(c *gin.Context) {
 		return
 	}
 
-	token, err := utils.GenerateToken(user.ID, user.Username)
+	token, refreshToken, ok := issueTokenPair(c, user, "")
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":       "Login successful",
+		"token":         token,
+		"refresh_token": refreshToken,
+		"expires_in":    int(utils.AccessTokenTTL().Seconds()),
+		"user":          authUser(user),
+	})
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// Обменять refresh-токен на новую пару токенов. Использованный токен
+// становится недействительным; его повторное предъявление отзывает
+// всю сессию.
+func RefreshToken(c *gin.Context) {
+	var req RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	refreshToken, userID, err := services.RotateRefreshToken(database.DB, req.RefreshToken, refreshTokenTTL())
+	switch {
+	case errors.Is(err, services.ErrRefreshTokenReused):
+		log.Printf("sessions: reused refresh token detected, session revoked")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Refresh token has already been used, please log in again"})
+		return
+	case errors.Is(err, services.ErrInvalidRefreshToken):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired refresh token"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to refresh token"})
+		return
+	}
+
+	var user models.User
+	if err := database.DB.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	token, err := generateAccessToken(user)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Login successful",
-		"token":   token,
-		"user": gin.H{
-			"id":        user.ID,
-			"username":  user.Username,
-			"email":     user.Email,
-			"first_name": user.FirstName,
-			"last_name":  user.LastName,
-			"role":      user.Role,
-		},
+		"token":         token,
+		"refresh_token": refreshToken,
+		"expires_in":    int(utils.AccessTokenTTL().Seconds()),
+		"user":          authUser(user),
+	})
+}
+
+// Завершить сессию: отозвать refresh-токен вместе со всеми токенами,
+// полученными из него обменом
+func Logout(c *gin.Context) {
+	var req RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := services.RevokeRefreshToken(database.DB, req.RefreshToken); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to log out"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Logged out",
+	})
+}
+
+// Выдать access-токен и refresh-токен. Пустой familyID начинает новую сессию.
+func issueTokenPair(c *gin.Context, user models.User, familyID string) (string, string, bool) {
+	token, err := generateAccessToken(user)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		return "", "", false
+	}
+
+	refreshToken, err := services.IssueRefreshToken(database.DB, user.ID, familyID, refreshTokenTTL())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate refresh token"})
+		return "", "", false
+	}
+	return token, refreshToken, true
+}
+
+// Access-токен несет роль и ресторан пользователя, чтобы обработчики
+// не загружали пользователя на каждый запрос
+func generateAccessToken(user models.User) (string, error) {
+	return utils.GenerateToken(utils.Claims{
+		UserID:       user.ID,
+		Username:     user.Username,
+		Role:         user.Role,
+		RestaurantID: user.RestaurantID,
+		TokenVersion: user.TokenVersion,
 	})
-} 
\ No newline at end of file
+}
+
+func refreshTokenTTL() time.Duration {
+	return time.Duration(config.AppConfig.JWT.RefreshTokenDays) * 24 * time.Hour
+}
+
+func authUser(user models.User) gin.H {
+	return gin.H{
+		"id":             user.ID,
+		"username":       user.Username,
+		"email":          user.Email,
+		"first_name":     user.FirstName,
+		"last_name":      user.LastName,
+		"role":           user.Role,
+		"restaurant_id":  user.RestaurantID,
+		"email_verified": user.EmailVerifiedAt != nil,
+	}
+}
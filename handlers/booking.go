@@ -1,22 +1,46 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"restaurant-booking/bookingstatus"
+	"restaurant-booking/config"
 	"restaurant-booking/database"
+	"restaurant-booking/middleware"
 	"restaurant-booking/models"
+	"restaurant-booking/services"
+	"restaurant-booking/utils"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
+// Если TableID не указан, столик подбирается автоматически
+// по стратегии ресторана с учетом LocationPreference.
 type CreateBookingRequest struct {
-	TableID    uint   `json:"table_id" binding:"required"`
+	TableID    uint   `json:"table_id"`
 	RestaurantID uint  `json:"restaurant_id" binding:"required"`
 	Date       string `json:"date" binding:"required"`
 	Time       string `json:"time" binding:"required"`
-	Duration   int    `json:"duration"`
-	Guests     int    `json:"guests" binding:"required"`
+	Duration   int    `json:"duration" binding:"omitempty,min=1"`
+	Guests     int    `json:"guests" binding:"required,min=1"`
 	Notes      string `json:"notes"`
+	LocationPreference string `json:"location_preference"`
+	CombinationID      uint   `json:"combination_id"` // бронирование сдвинутых столиков вместо TableID
+	HoldToken          string `json:"hold_token"`     // токен удержания, полученный через POST /holds
+}
+
+// Изменения, которые клиент может внести в свое бронирование.
+// Столик, ресторан, статус и владелец не меняются.
+type UpdateBookingRequest struct {
+	Date     *string `json:"date"`
+	Time     *string `json:"time"`
+	Duration *int    `json:"duration" binding:"omitempty,min=1"`
+	Guests   *int    `json:"guests" binding:"omitempty,min=1"`
+	Notes    *string `json:"notes"`
 }
 
 // Получить все бронирования пользователя
@@ -28,7 +52,7 @@ func GetUserBookings(c *gin.Context) {
 	}
 
 	var bookings []models.Booking
-	if err := database.DB.Where("user_id = ? AND status != ?", userID, "cancelled").Preload("Restaurant").Preload("Table").Find(&bookings).Error; err != nil {
+	if err := database.DB.Where("user_id = ? AND status != ?", userID, bookingstatus.Cancelled).Preload("Restaurant").Preload("Table").Preload("Tables").Find(&bookings).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch bookings"})
 		return
 	}
@@ -54,7 +78,7 @@ func GetBooking(c *gin.Context) {
 	}
 
 	var booking models.Booking
-	if err := database.DB.Where("id = ? AND user_id = ?", bookingID, userID).Preload("Restaurant").Preload("Table").First(&booking).Error; err != nil {
+	if err := database.DB.Where("id = ? AND user_id = ?", bookingID, userID).Preload("Restaurant").Preload("Table").Preload("Tables").First(&booking).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found"})
 		return
 	}
@@ -74,57 +98,206 @@ func CreateBooking(c *gin.Context) {
 
 	var req CreateBookingRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondValidationError(c, err)
 		return
 	}
 
-	// Проверяем, доступен ли столик
-	var table models.Table
-	if err := database.DB.Where("id = ? AND restaurant_id = ?", req.TableID, req.RestaurantID).First(&table).Error; err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Table not found"})
-		return
+	// Бронирование по удержанию получает удержанные столики и время
+	var hold *models.SlotHold
+	if req.HoldToken != "" {
+		var ok bool
+		if hold, ok = loadUserHold(c, req.HoldToken); !ok {
+			return
+		}
+		if hold.RestaurantID != req.RestaurantID || hold.Date != req.Date || hold.Time != req.Time ||
+			(req.Duration != 0 && req.Duration != hold.Duration) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Booking does not match the held slot"})
+			return
+		}
+		req.Duration = hold.Duration
+		req.TableID, req.CombinationID = hold.TableID, 0
+		if hold.TableCombinationID != nil {
+			req.CombinationID = *hold.TableCombinationID
+		}
 	}
 
-	if table.Status != "available" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Table is not available"})
+	booking, restaurant, ok := newBookingFromRequest(c, userID.(uint), req)
+	if !ok {
 		return
 	}
 
-	// Проверяем, нет ли конфликтующих бронирований
-	var existingBooking models.Booking
-	if err := database.DB.Where("table_id = ? AND date = ? AND status IN (?)", 
-		req.TableID, req.Date, []string{"pending", "confirmed"}).First(&existingBooking).Error; err == nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Table is already booked for this date"})
+	if err := services.CheckServiceHours(database.DB, restaurant, booking.StartsAt, booking.EndsAt); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Создаем бронирование
-	booking := models.Booking{
-		UserID:       userID.(uint),
-		TableID:      req.TableID,
-		RestaurantID: req.RestaurantID,
-		Date:         req.Date,
-		Time:         req.Time,
-		Duration:     req.Duration,
-		Guests:       req.Guests,
-		Notes:        req.Notes,
-		Status:       "pending",
+	// Проверка пересечений и создание выполняются в одной сериализуемой транзакции,
+	// чтобы параллельные запросы не могли забронировать один столик дважды.
+	// Транзакция может повторяться, поэтому каждая попытка работает с копией.
+	var created models.Booking
+	err := database.WithSerializableTx(func(tx *gorm.DB) error {
+		created = booking
+		// Удержание снимается в той же транзакции, поэтому оно не мешает проверке
+		if hold != nil {
+			if err := services.RedeemHold(tx, *hold); err != nil {
+				return err
+			}
+		}
+		if len(created.Tables) == 0 {
+			assignment, err := services.AssignTable(tx, restaurant, created.StartsAt, created.EndsAt, services.AssignmentRequest{
+				Guests:   created.Guests,
+				Location: req.LocationPreference,
+			})
+			if err != nil {
+				return err
+			}
+			created.Tables = assignment.Tables
+			created.TableID = assignment.Tables[0].ID
+			if assignment.Combination != nil {
+				created.TableCombinationID = &assignment.Combination.ID
+			}
+		} else if err := services.EnsureTablesFree(tx, services.TableIDs(created.Tables), created.StartsAt, created.EndsAt, 0); err != nil {
+			return err
+		}
+		// Столики уже существуют - создаем только связи в booking_tables
+		return tx.Omit("Tables.*").Create(&created).Error
+	})
+	if err != nil {
+		respondBookingTxError(c, err, "Failed to create booking")
+		return
 	}
+	booking = created
 
-	if err := database.DB.Create(&booking).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create booking"})
+	if err := database.DB.Preload("Restaurant").Preload("Table").Preload("Tables").First(&booking, booking.ID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load booking"})
 		return
 	}
 
-	// Обновляем статус столика
-	database.DB.Model(&table).Update("status", "booked")
-
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Booking created successfully",
 		"booking": booking,
 	})
 }
 
+// Собрать бронирование из запроса: проверить ресторан, выбранный столик или
+// комбинацию и размер компании. Часы работы проверяет вызывающий. Без столика и комбинации
+// Tables остается пустым - столик подбирается при сохранении.
+// При ошибке ответ уже отправлен.
+func newBookingFromRequest(c *gin.Context, userID uint, req CreateBookingRequest) (models.Booking, models.Restaurant, bool) {
+	booking, restaurant, ok := buildBookingFromRequest(c, userID, req)
+	if !ok {
+		return booking, restaurant, false
+	}
+
+	if err := services.CheckBookingHorizon(restaurant, booking.StartsAt, time.Now()); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return booking, restaurant, false
+	}
+
+	return booking, restaurant, true
+}
+
+// То же, что newBookingFromRequest, но без проверки горизонта бронирования:
+// для шаблона серии горизонт проверяется у каждого повторения отдельно
+func buildBookingFromRequest(c *gin.Context, userID uint, req CreateBookingRequest) (models.Booking, models.Restaurant, bool) {
+	var user models.User
+	if err := database.DB.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return models.Booking{}, models.Restaurant{}, false
+	}
+
+	if err := services.CheckEmailVerified(user, config.AppConfig.Account.RequireVerifiedEmail); err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Please verify your email before booking"})
+		return models.Booking{}, models.Restaurant{}, false
+	}
+
+	depositRequired, err := services.CheckNoShowPolicy(user, config.AppConfig.NoShow.Policy, config.AppConfig.NoShow.Threshold)
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Booking is blocked due to repeated no-shows"})
+		return models.Booking{}, models.Restaurant{}, false
+	}
+
+	var restaurant models.Restaurant
+	if err := database.DB.First(&restaurant, req.RestaurantID).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Restaurant not found"})
+		return models.Booking{}, restaurant, false
+	}
+
+	duration := req.Duration
+	if duration == 0 {
+		duration = defaultDuration(req.Guests)
+	}
+
+	booking := models.Booking{
+		UserID:       userID,
+		RestaurantID: req.RestaurantID,
+		Date:         req.Date,
+		Time:         req.Time,
+		Duration:     duration,
+		Guests:       req.Guests,
+		Notes:        req.Notes,
+		Status:       bookingstatus.Pending,
+		DepositRequired: depositRequired,
+	}
+
+	switch {
+	case req.CombinationID != 0:
+		// Компания садится за комбинацию сдвинутых столиков
+		var combination models.TableCombination
+		if err := database.DB.Where("id = ? AND restaurant_id = ?", req.CombinationID, req.RestaurantID).
+			Preload("Tables").First(&combination).Error; err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Table combination not found"})
+			return booking, restaurant, false
+		}
+
+		for _, table := range combination.Tables {
+			if table.Status != models.TableStatusAvailable {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Table is out of service"})
+				return booking, restaurant, false
+			}
+		}
+
+		if err := services.ValidateBookingParty(restaurant, combination.Capacity, combination.MinGuests, req.Guests, duration); err != nil {
+			respondValidationError(c, err)
+			return booking, restaurant, false
+		}
+		booking.TableID = combination.Tables[0].ID
+		booking.TableCombinationID = &combination.ID
+		booking.Tables = combination.Tables
+	case req.TableID != 0:
+		// Проверяем, доступен ли выбранный столик
+		var table models.Table
+		if err := database.DB.Where("id = ? AND restaurant_id = ?", req.TableID, req.RestaurantID).First(&table).Error; err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Table not found"})
+			return booking, restaurant, false
+		}
+
+		if table.Status != models.TableStatusAvailable {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Table is out of service"})
+			return booking, restaurant, false
+		}
+
+		if err := services.ValidateBookingParty(restaurant, table.Capacity, table.MinGuests, req.Guests, duration); err != nil {
+			respondValidationError(c, err)
+			return booking, restaurant, false
+		}
+		booking.TableID = table.ID
+		booking.Tables = []models.Table{table}
+	default:
+		if err := services.ValidatePartySize(restaurant, req.Guests); err != nil {
+			respondValidationError(c, err)
+			return booking, restaurant, false
+		}
+	}
+
+	if err := booking.SetWindow(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return booking, restaurant, false
+	}
+
+	return booking, restaurant, true
+}
+
 // Обновить бронирование
 func UpdateBooking(c *gin.Context) {
 	id := c.Param("id")
@@ -141,29 +314,106 @@ func UpdateBooking(c *gin.Context) {
 	}
 
 	var booking models.Booking
-	if err := database.DB.Where("id = ? AND user_id = ?", bookingID, userID).First(&booking).Error; err != nil {
+	if err := database.DB.Where("id = ? AND user_id = ?", bookingID, userID).Preload("Table").Preload("Tables").First(&booking).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found"})
 		return
 	}
 
-	var updateData models.Booking
-	if err := c.ShouldBindJSON(&updateData); err != nil {
+	if booking.Status != bookingstatus.Pending && booking.Status != bookingstatus.Confirmed {
+		c.JSON(http.StatusConflict, gin.H{"error": "Only pending or confirmed bookings can be changed"})
+		return
+	}
+
+	var req UpdateBookingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	// Применяем изменения к копии и заново проверяем бронирование целиком
+	candidate := booking
+	if req.Date != nil {
+		candidate.Date = *req.Date
+	}
+	if req.Time != nil {
+		candidate.Time = *req.Time
+	}
+	if req.Duration != nil {
+		candidate.Duration = *req.Duration
+	}
+	if req.Guests != nil {
+		candidate.Guests = *req.Guests
+	}
+	if req.Notes != nil {
+		candidate.Notes = *req.Notes
+	}
+	if err := candidate.SetWindow(); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	if err := database.DB.Model(&booking).Updates(updateData).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update booking"})
+	var restaurant models.Restaurant
+	if err := database.DB.First(&restaurant, booking.RestaurantID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load restaurant"})
+		return
+	}
+
+	// Для комбинации столиков действует ее собственная вместимость
+	capacity, minGuests := booking.Table.Capacity, booking.Table.MinGuests
+	if booking.TableCombinationID != nil {
+		var combination models.TableCombination
+		if err := database.DB.First(&combination, *booking.TableCombinationID).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load table combination"})
+			return
+		}
+		capacity, minGuests = combination.Capacity, combination.MinGuests
+	}
+
+	if err := services.ValidateBookingParty(restaurant, capacity, minGuests, candidate.Guests, candidate.Duration); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+	if err := services.CheckBookingHorizon(restaurant, candidate.StartsAt, time.Now()); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := services.CheckServiceHours(database.DB, restaurant, candidate.StartsAt, candidate.EndsAt); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = database.WithSerializableTx(func(tx *gorm.DB) error {
+		if err := services.EnsureTablesFree(tx, services.TableIDs(booking.Tables), candidate.StartsAt, candidate.EndsAt, booking.ID); err != nil {
+			return err
+		}
+		return tx.Model(&booking).Updates(map[string]interface{}{
+			"date":      candidate.Date,
+			"time":      candidate.Time,
+			"duration":  candidate.Duration,
+			"guests":    candidate.Guests,
+			"notes":     candidate.Notes,
+			"starts_at": candidate.StartsAt,
+			"ends_at":   candidate.EndsAt,
+		}).Error
+	})
+	if err != nil {
+		respondBookingTxError(c, err, "Failed to update booking")
+		return
+	}
+
+	var updated models.Booking
+	if err := database.DB.Preload("Restaurant").Preload("Table").Preload("Tables").First(&updated, booking.ID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load booking"})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Booking updated successfully",
-		"booking": booking,
+		"booking": updated,
 	})
 }
 
-// Отменить бронирование
+// Отменить бронирование по политике отмены ресторана
 func CancelBooking(c *gin.Context) {
 	id := c.Param("id")
 	bookingID, err := strconv.ParseUint(id, 10, 32)
@@ -179,38 +429,76 @@ func CancelBooking(c *gin.Context) {
 	}
 
 	var booking models.Booking
-	if err := database.DB.Where("id = ? AND user_id = ?", bookingID, userID).First(&booking).Error; err != nil {
+	if err := database.DB.Where("id = ? AND user_id = ?", bookingID, userID).Preload("Restaurant").First(&booking).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found"})
 		return
 	}
 
-	if booking.Status == "cancelled" {
+	if booking.Status == bookingstatus.Cancelled {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Booking is already cancelled"})
 		return
 	}
 
-	// Отменяем бронирование
-	if err := database.DB.Model(&booking).Update("status", "cancelled").Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to cancel booking"})
+	decision, err := cancelByCustomer(&booking)
+	if errors.Is(err, services.ErrCancellationClosed) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":        "Booking can no longer be cancelled",
+			"cancellation": decision,
+		})
 		return
 	}
+	if err != nil {
+		respondStatusError(c, err, "Failed to cancel booking")
+		return
+	}
+	offerFreedSlot(booking)
 
-	// Освобождаем столик
-	database.DB.Model(&models.Table{}).Where("id = ?", booking.TableID).Update("status", "available")
-
+	message := "Booking cancelled successfully"
+	if decision.Late {
+		message = "Booking cancelled; the cancellation is late and has been recorded"
+	}
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Booking cancelled successfully",
+		"message":      message,
+		"cancellation": decision,
 	})
 }
 
+// Отменить бронирование от имени клиента: проверить политику отмены ресторана
+// (booking.Restaurant должен быть загружен) и отметить позднюю отмену
+func cancelByCustomer(booking *models.Booking) (services.CancellationDecision, error) {
+	decision, err := services.EvaluateCancellation(booking.Restaurant, *booking, time.Now())
+	if err != nil {
+		return decision, err
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := services.ChangeBookingStatus(tx, booking, bookingstatus.Cancelled); err != nil {
+			return err
+		}
+		if !decision.Late {
+			return nil
+		}
+		return tx.Model(booking).Update("late_cancellation", true).Error
+	})
+	if err == nil {
+		booking.LateCancellation = decision.Late
+	}
+	return decision, err
+}
+
 // Получить доступные столики для ресторана
 func GetAvailableTables(c *gin.Context) {
-	restaurantID := c.Param("restaurant_id")
+	restaurantID, err := strconv.ParseUint(c.Param("restaurant_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restaurant ID"})
+		return
+	}
+
 	date := c.Query("date")
-	time := c.Query("time")
+	clock := c.Query("time")
 	guests := c.Query("guests")
 
-	if date == "" || time == "" || guests == "" {
+	if date == "" || clock == "" || guests == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Date, time and guests are required"})
 		return
 	}
@@ -221,43 +509,155 @@ func GetAvailableTables(c *gin.Context) {
 		return
 	}
 
-	var tables []models.Table
-	query := database.DB.Where("restaurant_id = ? AND capacity >= ? AND status = ?", 
-		restaurantID, guestsCount, "available")
-	
-	if err := query.Find(&tables).Error; err != nil {
+	duration := defaultDuration(guestsCount)
+	if d := c.Query("duration"); d != "" {
+		duration, err = strconv.Atoi(d)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid duration"})
+			return
+		}
+	}
+
+	start, end, err := utils.ParseBookingWindow(date, clock, duration)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var restaurant models.Restaurant
+	if err := database.DB.First(&restaurant, restaurantID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Restaurant not found"})
+		return
+	}
+
+	if err := services.ValidatePartySize(restaurant, guestsCount); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	if err := services.CheckBookingHorizon(restaurant, start, time.Now()); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := services.CheckServiceHours(database.DB, restaurant, start, end); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	query := services.AvailabilityQuery{
+		RestaurantID: uint(restaurantID),
+		Start:        start,
+		End:          end,
+		Guests:       guestsCount,
+	}
+	tables, err := services.FindFreeTables(database.DB, query)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tables"})
 		return
 	}
 
+	// Комбинации сдвинутых столиков для компаний, которым не хватает одного стола
+	combinations, err := services.FindFreeCombinations(database.DB, query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch table combinations"})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"tables": tables,
+		"tables":       tables,
+		"combinations": combinations,
 	})
 }
 
-func GetRestaurantBookings(c *gin.Context) {
-	userID, exists := c.Get("user_id")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+// Получить сетку слотов с доступными столиками на дату
+func GetAvailableSlots(c *gin.Context) {
+	restaurantID, err := strconv.ParseUint(c.Param("restaurant_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restaurant ID"})
 		return
 	}
 
-	var user models.User
-	if err := database.DB.Where("id = ?", userID).First(&user).Error; err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+	date := c.Query("date")
+	guests := c.Query("guests")
+	if date == "" || guests == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Date and guests are required"})
 		return
 	}
 
-	if user.Role != "restaurant_admin" && user.Role != "admin" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+	guestsCount, err := strconv.Atoi(guests)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid guests count"})
+		return
+	}
+
+	duration := defaultDuration(guestsCount)
+	if d := c.Query("duration"); d != "" {
+		duration, err = strconv.Atoi(d)
+		if err != nil || duration <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid duration"})
+			return
+		}
+	}
+
+	interval := config.AppConfig.Booking.SlotInterval
+	if i := c.Query("interval"); i != "" {
+		interval, err = strconv.Atoi(i)
+		if err != nil || interval <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid interval"})
+			return
+		}
+		if interval < services.MinSlotInterval {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Interval must be at least %d minutes", services.MinSlotInterval)})
+			return
+		}
+	}
+
+	var restaurant models.Restaurant
+	if err := database.DB.First(&restaurant, restaurantID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Restaurant not found"})
+		return
+	}
+
+	if err := services.ValidatePartySize(restaurant, guestsCount); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	slots, err := services.FindSlots(database.DB, restaurant, date, guestsCount, duration, interval)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	earliest, latest := services.BookingHorizon(restaurant, time.Now())
+	horizon := gin.H{"earliest": earliest}
+	if !latest.IsZero() {
+		horizon["latest"] = latest
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"date":     date,
+		"guests":   guestsCount,
+		"duration": duration,
+		"interval": interval,
+		"slots":    slots,
+		"horizon":  horizon,
+	})
+}
+
+func GetRestaurantBookings(c *gin.Context) {
+	principal, ok := middleware.CurrentPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
 		return
 	}
 
 	var bookings []models.Booking
-	query := database.DB.Preload("User").Preload("Table").Preload("Restaurant")
+	query := database.DB.Preload("User").Preload("Table").Preload("Tables").Preload("Restaurant")
 	
-	if user.Role == "restaurant_admin" && user.RestaurantID != nil {
-		query = query.Where("restaurant_id = ?", *user.RestaurantID)
+	if restaurantID, scoped := principal.RestaurantScope(); scoped {
+		query = query.Where("restaurant_id = ?", restaurantID)
 	}
 	
 	if err := query.Find(&bookings).Error; err != nil {
@@ -278,23 +678,12 @@ func UpdateBookingStatus(c *gin.Context) {
 		return
 	}
 
-	userID, exists := c.Get("user_id")
-	if !exists {
+	principal, ok := middleware.CurrentPrincipal(c)
+	if !ok {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
 		return
 	}
 
-	var user models.User
-	if err := database.DB.Where("id = ?", userID).First(&user).Error; err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
-		return
-	}
-
-	if user.Role != "restaurant_admin" && user.Role != "admin" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
-		return
-	}
-
 	var req struct {
 		Status string `json:"status" binding:"required"`
 	}
@@ -303,10 +692,16 @@ func UpdateBookingStatus(c *gin.Context) {
 		return
 	}
 
+	status, err := bookingstatus.Parse(req.Status)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	var booking models.Booking
 	query := database.DB.Where("id = ?", bookingID)
-	if user.Role == "restaurant_admin" && user.RestaurantID != nil {
-		query = query.Where("restaurant_id = ?", *user.RestaurantID)
+	if restaurantID, scoped := principal.RestaurantScope(); scoped {
+		query = query.Where("restaurant_id = ?", restaurantID)
 	}
 	
 	if err := query.First(&booking).Error; err != nil {
@@ -314,17 +709,56 @@ func UpdateBookingStatus(c *gin.Context) {
 		return
 	}
 
-	if err := database.DB.Model(&booking).Update("status", req.Status).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update booking status"})
+	if err := services.ChangeBookingStatus(database.DB, &booking, status); err != nil {
+		respondStatusError(c, err, "Failed to update booking status")
 		return
 	}
-
-	if req.Status == "cancelled" {
-		database.DB.Model(&models.Table{}).Where("id = ?", booking.TableID).Update("status", "available")
+	if status == bookingstatus.Cancelled {
+		offerFreedSlot(booking)
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Booking status updated successfully",
 		"booking": booking,
 	})
-} 
\ No newline at end of file
+}
+
+// Длительность бронирования, если клиент ее не указал: по правилам
+// конфигурации для размера компании, иначе общая по умолчанию
+func defaultDuration(guests int) int {
+	if duration := config.AppConfig.DefaultDuration(guests); duration > 0 {
+		return duration
+	}
+	return models.DefaultBookingDuration
+}
+
+// Ответить на ошибку смены статуса: недопустимый переход - 409
+func respondStatusError(c *gin.Context, err error, message string) {
+	if errors.Is(err, bookingstatus.ErrInvalidTransition) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+}
+
+// Ответить на ошибку транзакции, изменяющей занятость столика
+func respondBookingTxError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrTableUnavailable):
+		c.JSON(http.StatusConflict, gin.H{"error": "Table is already booked for this time"})
+	case errors.Is(err, services.ErrNoTableAvailable):
+		c.JSON(http.StatusConflict, gin.H{"error": "No table available for this party and time"})
+	case errors.Is(err, services.ErrTableBlocked):
+		c.JSON(http.StatusConflict, gin.H{"error": "Table is not available on this date"})
+	case errors.Is(err, services.ErrTableHeld):
+		c.JSON(http.StatusConflict, gin.H{"error": "Table is temporarily held by another customer"})
+	case errors.Is(err, services.ErrHoldLimit):
+		c.JSON(http.StatusConflict, gin.H{"error": "You already hold a table for this time, release it first"})
+	case errors.Is(err, services.ErrHoldExpired):
+		c.JSON(http.StatusConflict, gin.H{"error": "Hold has expired, please choose a table again"})
+	case database.IsSerializationFailure(err):
+		c.JSON(http.StatusConflict, gin.H{"error": "Table was booked concurrently, please try again"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
@@ -0,0 +1,289 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"restaurant-booking/bookingstatus"
+	"restaurant-booking/database"
+	"restaurant-booking/models"
+	"restaurant-booking/services"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Серия бронирований одного столика или комбинации. Date - первое повторение.
+// Если часть повторений занята, серия не создается, пока не передан SkipConflicts.
+type CreateBookingSeriesRequest struct {
+	RestaurantID  uint   `json:"restaurant_id" binding:"required"`
+	TableID       uint   `json:"table_id"`
+	CombinationID uint   `json:"combination_id"`
+	Date          string `json:"date" binding:"required"`
+	Time          string `json:"time" binding:"required"`
+	Duration      int    `json:"duration" binding:"omitempty,min=1"`
+	Guests        int    `json:"guests" binding:"required,min=1"`
+	Notes         string `json:"notes"`
+	Frequency     string `json:"frequency" binding:"required"`
+	Interval      int    `json:"interval" binding:"omitempty,min=1"`
+	Count         int    `json:"count"`
+	Until         string `json:"until"`
+	SkipConflicts bool   `json:"skip_conflicts"`
+}
+
+// Повторения серии заняты, а пропуск конфликтов не запрошен
+var errSeriesConflicts = errors.New("some occurrences of the series are not available")
+
+// Получить серии бронирований пользователя
+func GetUserBookingSeries(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	var series []models.BookingSeries
+	if err := database.DB.Where("user_id = ?", userID).Preload("Restaurant").
+		Preload("Bookings", func(db *gorm.DB) *gorm.DB { return db.Order("starts_at") }).
+		Find(&series).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch booking series"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"series": series,
+	})
+}
+
+// Получить серию бронирований по ID
+func GetBookingSeries(c *gin.Context) {
+	series, ok := loadUserBookingSeries(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"series": series,
+	})
+}
+
+// Создать серию повторяющихся бронирований
+func CreateBookingSeries(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	var req CreateBookingSeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	if req.TableID == 0 && req.CombinationID == 0 {
+		verr := &services.ValidationError{}
+		verr.Add("table_id", "table_id or combination_id is required")
+		respondValidationError(c, verr)
+		return
+	}
+
+	rule := services.RecurrenceRule{
+		Frequency: req.Frequency,
+		Interval:  req.Interval,
+		Count:     req.Count,
+		Until:     req.Until,
+	}
+	if err := services.ValidateRecurrence(req.Date, rule); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	// Столик, комбинация и размер компании проверяются один раз для всей серии;
+	// горизонт бронирования первой даты проверяется вместе с остальными повторениями
+	template, restaurant, ok := buildBookingFromRequest(c, userID.(uint), CreateBookingRequest{
+		TableID:       req.TableID,
+		RestaurantID:  req.RestaurantID,
+		Date:          req.Date,
+		Time:          req.Time,
+		Duration:      req.Duration,
+		Guests:        req.Guests,
+		Notes:         req.Notes,
+		CombinationID: req.CombinationID,
+	})
+	if !ok {
+		return
+	}
+
+	// Часы работы не зависят от транзакции, их проверяем заранее
+	var candidates []models.Booking
+	var closed []services.OccurrenceConflict
+	for _, date := range services.ExpandOccurrences(req.Date, rule) {
+		occurrence := template
+		occurrence.Date = date
+		if err := occurrence.SetWindow(); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err := services.CheckBookingHorizon(restaurant, occurrence.StartsAt, time.Now()); err != nil {
+			closed = append(closed, services.OccurrenceConflict{Date: date, Reason: err.Error()})
+			continue
+		}
+		if err := services.CheckServiceHours(database.DB, restaurant, occurrence.StartsAt, occurrence.EndsAt); err != nil {
+			closed = append(closed, services.OccurrenceConflict{Date: date, Reason: err.Error()})
+			continue
+		}
+		candidates = append(candidates, occurrence)
+	}
+
+	interval := req.Interval
+	if interval == 0 {
+		interval = 1
+	}
+
+	var series models.BookingSeries
+	var created []models.Booking
+	var conflicts []services.OccurrenceConflict
+	err := database.WithSerializableTx(func(tx *gorm.DB) error {
+		created = nil
+		conflicts = append([]services.OccurrenceConflict{}, closed...)
+
+		var free []models.Booking
+		for _, occurrence := range candidates {
+			if err := services.EnsureTablesFree(tx, services.TableIDs(occurrence.Tables), occurrence.StartsAt, occurrence.EndsAt, 0); err != nil {
+				if !errors.Is(err, services.ErrTableUnavailable) && !errors.Is(err, services.ErrTableBlocked) && !errors.Is(err, services.ErrTableHeld) {
+					return err
+				}
+				conflicts = append(conflicts, services.OccurrenceConflict{Date: occurrence.Date, Reason: err.Error()})
+				continue
+			}
+			free = append(free, occurrence)
+		}
+
+		if len(free) == 0 || (len(conflicts) > 0 && !req.SkipConflicts) {
+			return errSeriesConflicts
+		}
+
+		series = models.BookingSeries{
+			UserID:             template.UserID,
+			RestaurantID:       template.RestaurantID,
+			TableID:            template.TableID,
+			TableCombinationID: template.TableCombinationID,
+			Frequency:          req.Frequency,
+			Interval:           interval,
+			StartDate:          req.Date,
+			Until:              req.Until,
+			Count:              req.Count,
+			Time:               template.Time,
+			Duration:           template.Duration,
+			Guests:             template.Guests,
+			Notes:              template.Notes,
+			Status:             models.SeriesActive,
+		}
+		if err := tx.Create(&series).Error; err != nil {
+			return err
+		}
+
+		for _, occurrence := range free {
+			occurrence.SeriesID = &series.ID
+			// Столики уже существуют - создаем только связи в booking_tables
+			if err := tx.Omit("Tables.*").Create(&occurrence).Error; err != nil {
+				return err
+			}
+			created = append(created, occurrence)
+		}
+		return nil
+	})
+	if errors.Is(err, errSeriesConflicts) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":     "Some occurrences are not available; pass skip_conflicts to book the rest",
+			"conflicts": conflicts,
+		})
+		return
+	}
+	if err != nil {
+		respondBookingTxError(c, err, "Failed to create booking series")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":   "Booking series created successfully",
+		"series":    series,
+		"bookings":  created,
+		"conflicts": conflicts,
+	})
+}
+
+// Отменить серию: все будущие повторения, которые еще можно отменить по
+// политике отмены ресторана. Отдельное повторение отменяется как обычное бронирование.
+func CancelBookingSeries(c *gin.Context) {
+	series, ok := loadUserBookingSeries(c)
+	if !ok {
+		return
+	}
+
+	if series.Status == models.SeriesCancelled {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Booking series is already cancelled"})
+		return
+	}
+
+	cancelled, late := 0, 0
+	now := time.Now()
+	for i := range series.Bookings {
+		booking := &series.Bookings[i]
+		if !booking.StartsAt.After(now) || !bookingstatus.CanTransition(booking.Status, bookingstatus.Cancelled) {
+			continue
+		}
+		booking.Restaurant = series.Restaurant
+		decision, err := cancelByCustomer(booking)
+		if err != nil {
+			if errors.Is(err, bookingstatus.ErrInvalidTransition) || errors.Is(err, services.ErrCancellationClosed) {
+				continue
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to cancel booking series"})
+			return
+		}
+		cancelled++
+		if decision.Late {
+			late++
+		}
+		offerFreedSlot(*booking)
+	}
+
+	if err := database.DB.Model(&series).Update("status", models.SeriesCancelled).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to cancel booking series"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":   "Booking series cancelled successfully",
+		"cancelled": cancelled,
+		"late":      late,
+	})
+}
+
+// Загрузить серию текущего пользователя по :id вместе с повторениями
+func loadUserBookingSeries(c *gin.Context) (models.BookingSeries, bool) {
+	var series models.BookingSeries
+
+	seriesID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid booking series ID"})
+		return series, false
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return series, false
+	}
+
+	if err := database.DB.Where("id = ? AND user_id = ?", seriesID, userID).Preload("Restaurant").
+		Preload("Bookings", func(db *gorm.DB) *gorm.DB { return db.Order("starts_at") }).
+		First(&series).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Booking series not found"})
+		return series, false
+	}
+	return series, true
+}
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"restaurant-booking/config"
+	"restaurant-booking/database"
+	"restaurant-booking/models"
+	"restaurant-booking/utils"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Тесты, которым нужна база, выполняются на PostgreSQL из TEST_DATABASE_DSN,
+// например "host=localhost user=postgres password=password dbname=restaurant_booking_test sslmode=disable"
+func setupTestDB(t *testing.T) {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN is not set")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	database.DB = db
+	database.AutoMigrate()
+
+	config.AppConfig = &config.Config{}
+	config.AppConfig.Booking.SlotInterval = 30
+	config.AppConfig.Booking.HoldMinutes = 10
+	config.AppConfig.NoShow.Policy = "none"
+	config.AppConfig.NoShow.Threshold = 3
+}
+
+// Ресторан с одним столиком и пользователь; удаляются после теста
+func createBookingFixture(t *testing.T) (models.User, models.Restaurant, models.Table) {
+	t.Helper()
+
+	suffix := time.Now().UnixNano()
+	verifiedAt := time.Now()
+	user := models.User{
+		Username:        fmt.Sprintf("concurrency_%d", suffix),
+		Email:           fmt.Sprintf("concurrency_%d@example.com", suffix),
+		Password:        "x",
+		Role:            models.RoleCustomer,
+		EmailVerifiedAt: &verifiedAt,
+	}
+	if err := database.DB.Create(&user).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	restaurant := models.Restaurant{
+		Name:                  fmt.Sprintf("Concurrency %d", suffix),
+		Address:               "Test",
+		OpeningTime:           "10:00",
+		ClosingTime:           "23:00",
+		FreeCancellationHours: 24,
+	}
+	if err := database.DB.Create(&restaurant).Error; err != nil {
+		t.Fatalf("failed to create restaurant: %v", err)
+	}
+
+	table := models.Table{RestaurantID: restaurant.ID, Number: 1, Capacity: 4, Status: models.TableStatusAvailable}
+	if err := database.DB.Create(&table).Error; err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+
+	t.Cleanup(func() {
+		bookings := database.DB.Model(&models.Booking{}).Select("id").Where("restaurant_id = ?", restaurant.ID)
+		database.DB.Exec("DELETE FROM booking_tables WHERE booking_id IN (?)", bookings)
+		database.DB.Unscoped().Where("restaurant_id = ?", restaurant.ID).Delete(&models.Booking{})
+		database.DB.Unscoped().Delete(&table)
+		database.DB.Unscoped().Delete(&restaurant)
+		database.DB.Unscoped().Delete(&user)
+	})
+
+	return user, restaurant, table
+}
+
+// Параллельные запросы на один столик и время: ровно один создает
+// бронирование, остальные получают 409. Конфликты сериализации
+// повторяются WithSerializableTx или также дают 409.
+func TestCreateBookingConcurrentSameTable(t *testing.T) {
+	setupTestDB(t)
+	user, restaurant, table := createBookingFixture(t)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/bookings", func(c *gin.Context) {
+		c.Set("user_id", user.ID)
+	}, CreateBooking)
+
+	body, _ := json.Marshal(CreateBookingRequest{
+		TableID:      table.ID,
+		RestaurantID: restaurant.ID,
+		Date:         time.Now().AddDate(0, 0, 1).Format(utils.DateLayout),
+		Time:         "19:00",
+		Duration:     120,
+		Guests:       2,
+	})
+
+	const requests = 10
+	codes := make([]int, requests)
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			req := httptest.NewRequest(http.MethodPost, "/bookings", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			codes[i] = rec.Code
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	created, conflicts := 0, 0
+	for _, code := range codes {
+		switch code {
+		case http.StatusCreated:
+			created++
+		case http.StatusConflict:
+			conflicts++
+		default:
+			t.Errorf("unexpected status %d", code)
+		}
+	}
+	if created != 1 || conflicts != requests-1 {
+		t.Fatalf("got %d created and %d conflicts, want 1 and %d (codes %v)", created, conflicts, requests-1, codes)
+	}
+
+	var stored int64
+	database.DB.Model(&models.Booking{}).Where("restaurant_id = ?", restaurant.ID).Count(&stored)
+	if stored != 1 {
+		t.Fatalf("stored %d bookings, want 1", stored)
+	}
+}
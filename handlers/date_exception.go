@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"net/http"
+	"restaurant-booking/database"
+	"restaurant-booking/models"
+	"restaurant-booking/permission"
+	"restaurant-booking/services"
+	"restaurant-booking/utils"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type DateExceptionRequest struct {
+	Date     string `json:"date" binding:"required"`
+	Type     string `json:"type" binding:"required,oneof=closed hours tables"`
+	OpensAt  string `json:"opens_at"`
+	ClosesAt string `json:"closes_at"`
+	TableIDs []uint `json:"table_ids"`
+	Reason   string `json:"reason"`
+}
+
+// Получить исключения из расписания ресторана
+func GetDateExceptions(c *gin.Context) {
+	restaurant, ok := loadManagedRestaurant(c, permission.RestaurantManage)
+	if !ok {
+		return
+	}
+
+	query := database.DB.Where("restaurant_id = ?", restaurant.ID).Preload("Tables")
+	if from := c.Query("from"); from != "" {
+		query = query.Where("date >= ?", from)
+	}
+
+	var exceptions []models.DateException
+	if err := query.Order("date, opens_at").Find(&exceptions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch date exceptions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"date_exceptions": exceptions,
+	})
+}
+
+// Добавить исключение на дату
+func CreateDateException(c *gin.Context) {
+	restaurant, ok := loadManagedRestaurant(c, permission.RestaurantManage)
+	if !ok {
+		return
+	}
+
+	var req DateExceptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	exception := models.DateException{RestaurantID: restaurant.ID}
+	if !applyDateExceptionRequest(c, &exception, req) {
+		return
+	}
+
+	if err := database.DB.Create(&exception).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create date exception"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":        "Date exception created successfully",
+		"date_exception": exception,
+	})
+}
+
+// Изменить исключение на дату
+func UpdateDateException(c *gin.Context) {
+	restaurant, ok := loadManagedRestaurant(c, permission.RestaurantManage)
+	if !ok {
+		return
+	}
+
+	exception, ok := loadDateException(c, restaurant.ID)
+	if !ok {
+		return
+	}
+
+	var req DateExceptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	if !applyDateExceptionRequest(c, &exception, req) {
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&exception).Omit("Tables.*").Association("Tables").Replace(exception.Tables); err != nil {
+			return err
+		}
+		return tx.Omit("Tables").Save(&exception).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update date exception"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":        "Date exception updated successfully",
+		"date_exception": exception,
+	})
+}
+
+// Удалить исключение на дату
+func DeleteDateException(c *gin.Context) {
+	restaurant, ok := loadManagedRestaurant(c, permission.RestaurantManage)
+	if !ok {
+		return
+	}
+
+	exception, ok := loadDateException(c, restaurant.ID)
+	if !ok {
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&exception).Association("Tables").Clear(); err != nil {
+			return err
+		}
+		return tx.Delete(&exception).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete date exception"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Date exception deleted successfully",
+	})
+}
+
+func loadDateException(c *gin.Context, restaurantID uint) (models.DateException, bool) {
+	var exception models.DateException
+
+	exceptionID, err := strconv.ParseUint(c.Param("exception_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date exception ID"})
+		return exception, false
+	}
+
+	if err := database.DB.Where("id = ? AND restaurant_id = ?", exceptionID, restaurantID).
+		Preload("Tables").First(&exception).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Date exception not found"})
+		return exception, false
+	}
+
+	return exception, true
+}
+
+// Проверить запрос и перенести его в исключение
+func applyDateExceptionRequest(c *gin.Context, exception *models.DateException, req DateExceptionRequest) bool {
+	if _, err := time.Parse(utils.DateLayout, req.Date); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date, expected YYYY-MM-DD"})
+		return false
+	}
+
+	exception.Date = req.Date
+	exception.Type = req.Type
+	exception.Reason = req.Reason
+	exception.OpensAt = ""
+	exception.ClosesAt = ""
+	exception.Tables = nil
+
+	switch req.Type {
+	case models.DateExceptionHours:
+		if req.OpensAt == "" || req.ClosesAt == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "opens_at and closes_at are required for hours exception"})
+			return false
+		}
+		if err := services.ValidateOpeningHours(req.OpensAt, req.ClosesAt); err != nil {
+			respondValidationError(c, err)
+			return false
+		}
+		exception.OpensAt = req.OpensAt
+		exception.ClosesAt = req.ClosesAt
+	case models.DateExceptionTables:
+		if len(req.TableIDs) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "table_ids are required for tables exception"})
+			return false
+		}
+		var tables []models.Table
+		if err := database.DB.Where("id IN ? AND restaurant_id = ?", req.TableIDs, exception.RestaurantID).Find(&tables).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tables"})
+			return false
+		}
+		if len(tables) != len(req.TableIDs) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Some tables do not belong to this restaurant"})
+			return false
+		}
+		exception.Tables = tables
+	}
+
+	return true
+}
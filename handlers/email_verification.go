@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"restaurant-booking/config"
+	"restaurant-booking/database"
+	"restaurant-booking/mailer"
+	"restaurant-booking/models"
+	"restaurant-booking/services"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// Подтвердить email по токену из письма
+func VerifyEmail(c *gin.Context) {
+	var req VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	if _, err := services.VerifyEmail(database.DB, req.Token); err != nil {
+		if errors.Is(err, services.ErrInvalidVerificationToken) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired verification token"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify email"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Email verified successfully",
+	})
+}
+
+// Повторно отправить письмо подтверждения текущему пользователю
+func ResendVerificationEmail(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	var user models.User
+	if err := database.DB.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	retryAfter, err := sendEmailVerification(user)
+	switch {
+	case errors.Is(err, services.ErrEmailAlreadyVerified):
+		c.JSON(http.StatusConflict, gin.H{"error": "Email is already verified"})
+		return
+	case errors.Is(err, services.ErrVerificationThrottled):
+		seconds := int(math.Ceil(retryAfter.Seconds()))
+		c.Header("Retry-After", strconv.Itoa(seconds))
+		c.JSON(http.StatusTooManyRequests, gin.H{
+			"error":       "Verification email was sent recently, please try again later",
+			"retry_after": seconds,
+		})
+		return
+	case err != nil:
+		log.Printf("email verification: failed to send to user %d: %v", user.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send verification email"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Verification email sent",
+	})
+}
+
+// Создать токен подтверждения и отправить письмо со ссылкой. При
+// ErrVerificationThrottled возвращает время до следующей попытки.
+func sendEmailVerification(user models.User) (time.Duration, error) {
+	account := config.AppConfig.Account
+	token, retryAfter, err := services.CreateEmailVerification(database.DB, user,
+		time.Duration(account.EmailVerificationHours)*time.Hour,
+		time.Duration(account.VerificationResendSeconds)*time.Second)
+	if err != nil {
+		return retryAfter, err
+	}
+
+	body := fmt.Sprintf("Здравствуйте, %s!\n\nПодтвердите адрес электронной почты, перейдя по ссылке:\n%s\n\n"+
+		"Ссылка действует %d ч. Если вы не регистрировались, просто проигнорируйте это письмо.",
+		user.Username, appLink("/verify-email", token), account.EmailVerificationHours)
+	return 0, mailer.Send(user.Email, "Подтверждение email", body)
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"net/http"
+	"restaurant-booking/config"
+	"restaurant-booking/database"
+	"restaurant-booking/models"
+	"restaurant-booking/services"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Удержать столик на время оформления бронирования. Параметры те же, что у
+// CreateBooking; без table_id и combination_id столик подбирается автоматически.
+// Возвращает токен, который передается в CreateBooking как hold_token.
+func CreateHold(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	var req CreateBookingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	booking, restaurant, ok := newBookingFromRequest(c, userID.(uint), req)
+	if !ok {
+		return
+	}
+
+	if err := services.CheckServiceHours(database.DB, restaurant, booking.StartsAt, booking.EndsAt); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	token, err := services.NewHoldToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create hold"})
+		return
+	}
+
+	ttl := time.Duration(config.AppConfig.Booking.HoldMinutes) * time.Minute
+
+	var hold models.SlotHold
+	err = database.WithSerializableTx(func(tx *gorm.DB) error {
+		if err := services.EnsureHoldAllowed(tx, booking.UserID, booking.RestaurantID, booking.StartsAt, booking.EndsAt); err != nil {
+			return err
+		}
+		hold = models.SlotHold{
+			Token:              token,
+			UserID:             booking.UserID,
+			RestaurantID:       booking.RestaurantID,
+			TableID:            booking.TableID,
+			TableCombinationID: booking.TableCombinationID,
+			Date:               booking.Date,
+			Time:               booking.Time,
+			Duration:           booking.Duration,
+			Guests:             booking.Guests,
+			StartsAt:           booking.StartsAt,
+			EndsAt:             booking.EndsAt,
+			ExpiresAt:          time.Now().Add(ttl),
+			Tables:             booking.Tables,
+		}
+		if len(hold.Tables) == 0 {
+			assignment, err := services.AssignTable(tx, restaurant, hold.StartsAt, hold.EndsAt, services.AssignmentRequest{
+				Guests:   hold.Guests,
+				Location: req.LocationPreference,
+			})
+			if err != nil {
+				return err
+			}
+			hold.Tables = assignment.Tables
+			hold.TableID = assignment.Tables[0].ID
+			if assignment.Combination != nil {
+				hold.TableCombinationID = &assignment.Combination.ID
+			}
+		} else if err := services.EnsureTablesFree(tx, services.TableIDs(hold.Tables), hold.StartsAt, hold.EndsAt, 0); err != nil {
+			return err
+		}
+		return tx.Omit("Tables.*").Create(&hold).Error
+	})
+	if err != nil {
+		respondBookingTxError(c, err, "Failed to create hold")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Table held",
+		"hold":    hold,
+	})
+}
+
+// Снять удержание до истечения срока
+func ReleaseHold(c *gin.Context) {
+	hold, ok := loadUserHold(c, c.Param("token"))
+	if !ok {
+		return
+	}
+
+	if err := services.ReleaseHold(database.DB, *hold); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to release hold"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Hold released",
+	})
+}
+
+// Загрузить действующее удержание текущего пользователя по токену
+func loadUserHold(c *gin.Context, token string) (*models.SlotHold, bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return nil, false
+	}
+
+	var hold models.SlotHold
+	if err := database.DB.Where("token = ? AND user_id = ?", token, userID).First(&hold).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Hold not found"})
+		return nil, false
+	}
+	if !hold.ExpiresAt.After(time.Now()) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Hold has expired, please choose a table again"})
+		return nil, false
+	}
+	return &hold, true
+}
@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"net/http"
+	"restaurant-booking/database"
+	"restaurant-booking/models"
+	"restaurant-booking/permission"
+	"restaurant-booking/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OpeningHoursRequest struct {
+	Weekday  *int   `json:"weekday" binding:"required"`
+	OpensAt  string `json:"opens_at" binding:"required"`
+	ClosesAt string `json:"closes_at" binding:"required"`
+}
+
+// Получить недельное расписание ресторана
+func GetOpeningHours(c *gin.Context) {
+	restaurant, ok := loadManagedRestaurant(c, permission.RestaurantManage)
+	if !ok {
+		return
+	}
+
+	var hours []models.OpeningHours
+	if err := database.DB.Where("restaurant_id = ?", restaurant.ID).Order("weekday, opens_at").Find(&hours).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch opening hours"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"opening_hours": hours,
+	})
+}
+
+// Добавить интервал работы
+func CreateOpeningHours(c *gin.Context) {
+	restaurant, ok := loadManagedRestaurant(c, permission.RestaurantManage)
+	if !ok {
+		return
+	}
+
+	var req OpeningHoursRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	hours := models.OpeningHours{
+		RestaurantID: restaurant.ID,
+		Weekday:      *req.Weekday,
+		OpensAt:      req.OpensAt,
+		ClosesAt:     req.ClosesAt,
+	}
+	if !validateScheduleWith(c, restaurant.ID, hours) {
+		return
+	}
+
+	if err := database.DB.Create(&hours).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create opening hours"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":       "Opening hours created successfully",
+		"opening_hours": hours,
+	})
+}
+
+// Изменить интервал работы
+func UpdateOpeningHours(c *gin.Context) {
+	restaurant, ok := loadManagedRestaurant(c, permission.RestaurantManage)
+	if !ok {
+		return
+	}
+
+	hours, ok := loadOpeningHours(c, restaurant.ID)
+	if !ok {
+		return
+	}
+
+	var req OpeningHoursRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	hours.Weekday = *req.Weekday
+	hours.OpensAt = req.OpensAt
+	hours.ClosesAt = req.ClosesAt
+	if !validateScheduleWith(c, restaurant.ID, hours) {
+		return
+	}
+
+	if err := database.DB.Save(&hours).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update opening hours"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":       "Opening hours updated successfully",
+		"opening_hours": hours,
+	})
+}
+
+// Удалить интервал работы
+func DeleteOpeningHours(c *gin.Context) {
+	restaurant, ok := loadManagedRestaurant(c, permission.RestaurantManage)
+	if !ok {
+		return
+	}
+
+	hours, ok := loadOpeningHours(c, restaurant.ID)
+	if !ok {
+		return
+	}
+
+	if err := database.DB.Delete(&hours).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete opening hours"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Opening hours deleted successfully",
+	})
+}
+
+func loadOpeningHours(c *gin.Context, restaurantID uint) (models.OpeningHours, bool) {
+	var hours models.OpeningHours
+
+	hoursID, err := strconv.ParseUint(c.Param("hours_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid opening hours ID"})
+		return hours, false
+	}
+
+	if err := database.DB.Where("id = ? AND restaurant_id = ?", hoursID, restaurantID).First(&hours).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Opening hours not found"})
+		return hours, false
+	}
+
+	return hours, true
+}
+
+// Проверить расписание ресторана с добавленным или измененным интервалом
+func validateScheduleWith(c *gin.Context, restaurantID uint, hours models.OpeningHours) bool {
+	var schedule []models.OpeningHours
+	if err := database.DB.Where("restaurant_id = ? AND id <> ?", restaurantID, hours.ID).Find(&schedule).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch opening hours"})
+		return false
+	}
+
+	if err := services.ValidateWeeklySchedule(append(schedule, hours)); err != nil {
+		respondValidationError(c, err)
+		return false
+	}
+	return true
+}
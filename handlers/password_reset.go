@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"restaurant-booking/config"
+	"restaurant-booking/database"
+	"restaurant-booking/mailer"
+	"restaurant-booking/models"
+	"restaurant-booking/services"
+	"restaurant-booking/utils"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PasswordResetRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type PasswordResetConfirmRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=6"`
+}
+
+// Запросить сброс пароля. Ответ не зависит от того, зарегистрирован ли
+// адрес, чтобы по нему нельзя было перебирать пользователей.
+func RequestPasswordReset(c *gin.Context) {
+	var req PasswordResetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	// Токен и письмо создаются вне запроса: иначе время ответа выдавало бы,
+	// зарегистрирован ли адрес
+	var user models.User
+	if err := database.DB.Where("email = ?", req.Email).First(&user).Error; err == nil {
+		go sendPasswordReset(user)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "If the email is registered, a password reset link has been sent",
+	})
+}
+
+// Установить новый пароль по токену из письма. Все сессии пользователя
+// завершаются, войти нужно заново.
+func ConfirmPasswordReset(c *gin.Context) {
+	var req PasswordResetConfirmRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	hashedPassword, err := utils.HashPassword(req.Password)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to hash password"})
+		return
+	}
+
+	if _, err := services.ResetPassword(database.DB, req.Token, hashedPassword); err != nil {
+		if errors.Is(err, services.ErrInvalidResetToken) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired password reset token"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reset password"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Password has been reset, please log in again",
+	})
+}
+
+// Создать токен и отправить письмо со ссылкой. Ошибки только логируются:
+// ответ на запрос сброса не должен их раскрывать.
+func sendPasswordReset(user models.User) {
+	ttl := time.Duration(config.AppConfig.Account.PasswordResetMinutes) * time.Minute
+	token, err := services.CreatePasswordReset(database.DB, user.ID, ttl)
+	if err != nil {
+		log.Printf("password reset: failed to create token for user %d: %v", user.ID, err)
+		return
+	}
+
+	body := fmt.Sprintf("Здравствуйте, %s!\n\nЧтобы задать новый пароль, перейдите по ссылке:\n%s\n\n"+
+		"Ссылка действует %d минут и может быть использована один раз. "+
+		"Если вы не запрашивали сброс пароля, просто проигнорируйте это письмо.",
+		user.Username, appLink("/reset-password", token), config.AppConfig.Account.PasswordResetMinutes)
+	if err := mailer.Send(user.Email, "Сброс пароля", body); err != nil {
+		log.Printf("password reset: failed to send mail to user %d: %v", user.ID, err)
+	}
+}
+
+// Ссылка на страницу фронтенда с токеном в параметре token
+func appLink(path, token string) string {
+	return strings.TrimRight(config.AppConfig.Mail.AppURL, "/") + path + "?token=" + url.QueryEscape(token)
+}
@@ -4,9 +4,15 @@ import (
 	"net/http"
 	"strconv"
 	"restaurant-booking/database"
+	"restaurant-booking/middleware"
+	"restaurant-booking/permission"
 	"restaurant-booking/models"
+	"restaurant-booking/services"
+	"restaurant-booking/utils"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // Получить все рестораны
@@ -33,7 +39,13 @@ func GetRestaurant(c *gin.Context) {
 	}
 
 	var restaurant models.Restaurant
-	if err := database.DB.Preload("Tables").First(&restaurant, restaurantID).Error; err != nil {
+	// Предстоящие исключения нужны клиенту, чтобы отметить недоступные даты
+	today := time.Now().Format(utils.DateLayout)
+	if err := database.DB.Preload("Tables").Preload("OpeningHours", func(db *gorm.DB) *gorm.DB {
+		return db.Order("weekday, opens_at")
+	}).Preload("DateExceptions", func(db *gorm.DB) *gorm.DB {
+		return db.Where("date >= ?", today).Order("date, opens_at")
+	}).Preload("DateExceptions.Tables").Preload("TableCombinations.Tables").First(&restaurant, restaurantID).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Restaurant not found"})
 		return
 	}
@@ -43,15 +55,91 @@ func GetRestaurant(c *gin.Context) {
 	})
 }
 
+// Ресторан в запросах создания и изменения. Настройки, для которых 0 -
+// осмысленное значение, - указатели: при изменении nil означает "не менять",
+// а переданный 0 нужно сохранить (обновление структурой его пропускает).
+type RestaurantRequest struct {
+	models.Restaurant
+	MaxPartySize              *int `json:"max_party_size"`
+	FreeCancellationHours     *int `json:"free_cancellation_hours"`
+	CancellationCutoffMinutes *int `json:"cancellation_cutoff_minutes"`
+	MinLeadMinutes            *int `json:"min_lead_minutes"`
+	MaxAdvanceDays            *int `json:"max_advance_days"`
+	TurnoverBufferMinutes     *int `json:"turnover_buffer_minutes"`
+}
+
+// Перенести переданные настройки-указатели в ресторан и вернуть их колонки
+func (r RestaurantRequest) applySettings(restaurant *models.Restaurant) map[string]interface{} {
+	changes := map[string]interface{}{}
+	set := func(column string, value *int, field *int) {
+		if value != nil {
+			changes[column] = *value
+			*field = *value
+		}
+	}
+
+	set("max_party_size", r.MaxPartySize, &restaurant.MaxPartySize)
+	set("free_cancellation_hours", r.FreeCancellationHours, &restaurant.FreeCancellationHours)
+	set("cancellation_cutoff_minutes", r.CancellationCutoffMinutes, &restaurant.CancellationCutoffMinutes)
+	set("min_lead_minutes", r.MinLeadMinutes, &restaurant.MinLeadMinutes)
+	set("max_advance_days", r.MaxAdvanceDays, &restaurant.MaxAdvanceDays)
+	set("turnover_buffer_minutes", r.TurnoverBufferMinutes, &restaurant.TurnoverBufferMinutes)
+	return changes
+}
+
 // Создать новый ресторан
 func CreateRestaurant(c *gin.Context) {
-	var restaurant models.Restaurant
-	if err := c.ShouldBindJSON(&restaurant); err != nil {
+	var req RestaurantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	if err := database.DB.Create(&restaurant).Error; err != nil {
+	restaurant := req.Restaurant
+	settings := req.applySettings(&restaurant)
+
+	if err := services.ValidateOpeningHours(restaurant.OpeningTime, restaurant.ClosingTime); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := services.ValidatePartyLimits(restaurant.MinPartySize, restaurant.MaxPartySize); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	if restaurant.AssignmentStrategy != "" && !services.HasAssigner(restaurant.AssignmentStrategy) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown assignment strategy"})
+		return
+	}
+
+	if err := services.ValidateCancellationPolicy(restaurant.FreeCancellationHours, restaurant.CancellationCutoffMinutes); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	if err := services.ValidateBookingHorizon(restaurant.MinLeadMinutes, restaurant.MaxAdvanceDays); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	if err := services.ValidateTurnoverBuffer(restaurant.TurnoverBufferMinutes); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&restaurant).Error; err != nil {
+			return err
+		}
+		if len(settings) == 0 {
+			return nil
+		}
+		// Нулевые значения колонок со значением по умолчанию (free_cancellation_hours)
+		// GORM при создании не записывает - сохраняем переданные настройки явно
+		return tx.Model(&restaurant).Updates(settings).Error
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create restaurant"})
 		return
 	}
@@ -64,26 +152,72 @@ func CreateRestaurant(c *gin.Context) {
 
 // Обновить ресторан
 func UpdateRestaurant(c *gin.Context) {
-	id := c.Param("id")
-	restaurantID, err := strconv.ParseUint(id, 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restaurant ID"})
+	restaurant, ok := loadManagedRestaurant(c, permission.RestaurantManage)
+	if !ok {
 		return
 	}
 
-	var restaurant models.Restaurant
-	if err := database.DB.First(&restaurant, restaurantID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Restaurant not found"})
+	var updateData RestaurantRequest
+	if err := c.ShouldBindJSON(&updateData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	var updateData models.Restaurant
-	if err := c.ShouldBindJSON(&updateData); err != nil {
+	// Настройки проверяются вместе с текущими значениями непереданных полей
+	merged := restaurant
+	settings := updateData.applySettings(&merged)
+
+	opening, closing := restaurant.OpeningTime, restaurant.ClosingTime
+	if updateData.OpeningTime != "" {
+		opening = updateData.OpeningTime
+	}
+	if updateData.ClosingTime != "" {
+		closing = updateData.ClosingTime
+	}
+	if err := services.ValidateOpeningHours(opening, closing); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	if err := database.DB.Model(&restaurant).Updates(updateData).Error; err != nil {
+	minParty := restaurant.MinPartySize
+	if updateData.MinPartySize != 0 {
+		minParty = updateData.MinPartySize
+	}
+	if err := services.ValidatePartyLimits(minParty, merged.MaxPartySize); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	if updateData.AssignmentStrategy != "" && !services.HasAssigner(updateData.AssignmentStrategy) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown assignment strategy"})
+		return
+	}
+
+	if err := services.ValidateCancellationPolicy(merged.FreeCancellationHours, merged.CancellationCutoffMinutes); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	if err := services.ValidateBookingHorizon(merged.MinLeadMinutes, merged.MaxAdvanceDays); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	if err := services.ValidateTurnoverBuffer(merged.TurnoverBufferMinutes); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&restaurant).Updates(updateData.Restaurant).Error; err != nil {
+			return err
+		}
+		if len(settings) == 0 {
+			return nil
+		}
+		return tx.Model(&restaurant).Updates(settings).Error
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update restaurant"})
 		return
 	}
@@ -96,16 +230,8 @@ func UpdateRestaurant(c *gin.Context) {
 
 // Удалить ресторан
 func DeleteRestaurant(c *gin.Context) {
-	id := c.Param("id")
-	restaurantID, err := strconv.ParseUint(id, 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restaurant ID"})
-		return
-	}
-
-	var restaurant models.Restaurant
-	if err := database.DB.First(&restaurant, restaurantID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Restaurant not found"})
+	restaurant, ok := loadManagedRestaurant(c, permission.RestaurantDelete)
+	if !ok {
 		return
 	}
 
@@ -117,4 +243,28 @@ func DeleteRestaurant(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Restaurant deleted successfully",
 	})
-} 
\ No newline at end of file
+} 
+// Загрузить ресторан из параметра :id и проверить право пользователя
+// в отношении этого ресторана
+func loadManagedRestaurant(c *gin.Context, perm permission.Permission) (models.Restaurant, bool) {
+	var restaurant models.Restaurant
+
+	restaurantID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restaurant ID"})
+		return restaurant, false
+	}
+
+	if err := database.DB.First(&restaurant, restaurantID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Restaurant not found"})
+		return restaurant, false
+	}
+
+	principal, ok := middleware.CurrentPrincipal(c)
+	if !ok || !principal.CanInRestaurant(perm, restaurant.ID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return restaurant, false
+	}
+
+	return restaurant, true
+}
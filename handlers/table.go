@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"net/http"
+	"restaurant-booking/bookingstatus"
+	"restaurant-booking/database"
+	"restaurant-booking/models"
+	"restaurant-booking/permission"
+	"restaurant-booking/services"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Столик ресторана. Чтобы вывести столик из работы, не удаляя его,
+// передайте status "out_of_service".
+type TableRequest struct {
+	Number                int    `json:"number" binding:"required,min=1"`
+	Capacity              int    `json:"capacity" binding:"required,min=1"`
+	MinGuests             int    `json:"min_guests" binding:"omitempty,min=0"`
+	Location              string `json:"location"`
+	Status                string `json:"status" binding:"omitempty,oneof=available out_of_service"`
+	TurnoverBufferMinutes *int   `json:"turnover_buffer_minutes" binding:"omitempty,min=0"`
+}
+
+// Получить все столики ресторана, включая выведенные из работы
+func GetTables(c *gin.Context) {
+	restaurant, ok := loadManagedRestaurant(c, permission.TableManage)
+	if !ok {
+		return
+	}
+
+	var tables []models.Table
+	if err := database.DB.Where("restaurant_id = ?", restaurant.ID).Order("number").Find(&tables).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tables"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tables": tables,
+	})
+}
+
+// Создать столик
+func CreateTable(c *gin.Context) {
+	restaurant, ok := loadManagedRestaurant(c, permission.TableManage)
+	if !ok {
+		return
+	}
+
+	var req TableRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	table := models.Table{RestaurantID: restaurant.ID}
+	if !applyTableRequest(c, &table, req) {
+		return
+	}
+
+	if err := database.DB.Create(&table).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create table"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Table created successfully",
+		"table":   table,
+	})
+}
+
+// Изменить столик. Существующие бронирования сохраняются.
+func UpdateTable(c *gin.Context) {
+	restaurant, ok := loadManagedRestaurant(c, permission.TableManage)
+	if !ok {
+		return
+	}
+
+	table, ok := loadTable(c, restaurant.ID)
+	if !ok {
+		return
+	}
+
+	var req TableRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	if !applyTableRequest(c, &table, req) {
+		return
+	}
+
+	if err := database.DB.Save(&table).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update table"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Table updated successfully",
+		"table":   table,
+	})
+}
+
+// Удалить столик. Столик с будущими бронированиями или входящий в комбинацию
+// удалить нельзя - его можно вывести из работы.
+func DeleteTable(c *gin.Context) {
+	restaurant, ok := loadManagedRestaurant(c, permission.TableManage)
+	if !ok {
+		return
+	}
+
+	table, ok := loadTable(c, restaurant.ID)
+	if !ok {
+		return
+	}
+
+	var upcoming int64
+	if err := database.DB.Table("booking_tables").
+		Joins("JOIN bookings ON bookings.id = booking_tables.booking_id").
+		Where("booking_tables.table_id = ? AND bookings.deleted_at IS NULL AND bookings.status IN ? AND bookings.ends_at > ?",
+			table.ID, bookingstatus.Active, time.Now()).
+		Count(&upcoming).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check table bookings"})
+		return
+	}
+	if upcoming > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Table has upcoming bookings; set its status to out_of_service instead"})
+		return
+	}
+
+	var combined int64
+	if err := database.DB.Table("table_combination_tables").Where("table_id = ?", table.ID).Count(&combined).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check table combinations"})
+		return
+	}
+	if combined > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Table is part of a table combination; remove it from the combination first"})
+		return
+	}
+
+	if err := database.DB.Delete(&table).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete table"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Table deleted successfully",
+	})
+}
+
+func loadTable(c *gin.Context, restaurantID uint) (models.Table, bool) {
+	var table models.Table
+
+	tableID, err := strconv.ParseUint(c.Param("table_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid table ID"})
+		return table, false
+	}
+
+	if err := database.DB.Where("id = ? AND restaurant_id = ?", tableID, restaurantID).First(&table).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Table not found"})
+		return table, false
+	}
+
+	return table, true
+}
+
+// Проверить столик и перенести запрос в модель: номер уникален в ресторане,
+// минимальная загрузка не больше вместимости
+func applyTableRequest(c *gin.Context, table *models.Table, req TableRequest) bool {
+	if req.MinGuests > req.Capacity {
+		verr := &services.ValidationError{}
+		verr.Add("min_guests", "must not exceed capacity")
+		respondValidationError(c, verr)
+		return false
+	}
+
+	var duplicates int64
+	if err := database.DB.Model(&models.Table{}).
+		Where("restaurant_id = ? AND number = ? AND id <> ?", table.RestaurantID, req.Number, table.ID).
+		Count(&duplicates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check table number"})
+		return false
+	}
+	if duplicates > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Table with this number already exists in the restaurant"})
+		return false
+	}
+
+	status := req.Status
+	if status == "" {
+		status = models.TableStatusAvailable
+	}
+
+	table.Number = req.Number
+	table.Capacity = req.Capacity
+	table.MinGuests = req.MinGuests
+	table.Location = req.Location
+	table.Status = status
+	table.TurnoverBufferMinutes = req.TurnoverBufferMinutes
+	return true
+}
@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"net/http"
+	"restaurant-booking/bookingstatus"
+	"restaurant-booking/database"
+	"restaurant-booking/models"
+	"restaurant-booking/permission"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type TableCombinationRequest struct {
+	Name      string `json:"name"`
+	TableIDs  []uint `json:"table_ids" binding:"required,min=2"`
+	Capacity  int    `json:"capacity" binding:"omitempty,min=1"` // по умолчанию - сумма вместимостей
+	MinGuests int    `json:"min_guests" binding:"omitempty,min=0"`
+}
+
+// Получить комбинации столиков ресторана
+func GetTableCombinations(c *gin.Context) {
+	restaurant, ok := loadManagedRestaurant(c, permission.TableManage)
+	if !ok {
+		return
+	}
+
+	var combinations []models.TableCombination
+	if err := database.DB.Where("restaurant_id = ?", restaurant.ID).Preload("Tables").Order("capacity, id").Find(&combinations).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch table combinations"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"combinations": combinations,
+	})
+}
+
+// Создать комбинацию столиков
+func CreateTableCombination(c *gin.Context) {
+	restaurant, ok := loadManagedRestaurant(c, permission.TableManage)
+	if !ok {
+		return
+	}
+
+	var req TableCombinationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	combination := models.TableCombination{RestaurantID: restaurant.ID}
+	if !applyTableCombinationRequest(c, &combination, req) {
+		return
+	}
+
+	if err := database.DB.Omit("Tables.*").Create(&combination).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create table combination"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":     "Table combination created successfully",
+		"combination": combination,
+	})
+}
+
+// Изменить комбинацию столиков
+func UpdateTableCombination(c *gin.Context) {
+	restaurant, ok := loadManagedRestaurant(c, permission.TableManage)
+	if !ok {
+		return
+	}
+
+	combination, ok := loadTableCombination(c, restaurant.ID)
+	if !ok {
+		return
+	}
+
+	var req TableCombinationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	if !applyTableCombinationRequest(c, &combination, req) {
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&combination).Omit("Tables.*").Association("Tables").Replace(combination.Tables); err != nil {
+			return err
+		}
+		return tx.Omit("Tables").Save(&combination).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update table combination"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":     "Table combination updated successfully",
+		"combination": combination,
+	})
+}
+
+// Удалить комбинацию столиков. Существующие бронирования сохраняют свои столики.
+func DeleteTableCombination(c *gin.Context) {
+	restaurant, ok := loadManagedRestaurant(c, permission.TableManage)
+	if !ok {
+		return
+	}
+
+	combination, ok := loadTableCombination(c, restaurant.ID)
+	if !ok {
+		return
+	}
+
+	// Вместимость предстоящих бронирований определяется комбинацией
+	var upcoming int64
+	if err := database.DB.Model(&models.Booking{}).
+		Where("table_combination_id = ? AND status IN ? AND ends_at > ?", combination.ID, bookingstatus.Active, time.Now()).
+		Count(&upcoming).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check combination bookings"})
+		return
+	}
+	if upcoming > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Table combination has upcoming bookings"})
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&combination).Association("Tables").Clear(); err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Booking{}).Where("table_combination_id = ?", combination.ID).
+			Update("table_combination_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&combination).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete table combination"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Table combination deleted successfully",
+	})
+}
+
+func loadTableCombination(c *gin.Context, restaurantID uint) (models.TableCombination, bool) {
+	var combination models.TableCombination
+
+	combinationID, err := strconv.ParseUint(c.Param("combination_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid table combination ID"})
+		return combination, false
+	}
+
+	if err := database.DB.Where("id = ? AND restaurant_id = ?", combinationID, restaurantID).
+		Preload("Tables").First(&combination).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Table combination not found"})
+		return combination, false
+	}
+
+	return combination, true
+}
+
+// Проверить столики комбинации и перенести запрос в модель
+func applyTableCombinationRequest(c *gin.Context, combination *models.TableCombination, req TableCombinationRequest) bool {
+	var tables []models.Table
+	if err := database.DB.Where("id IN ? AND restaurant_id = ?", req.TableIDs, combination.RestaurantID).Find(&tables).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tables"})
+		return false
+	}
+	if len(tables) != len(req.TableIDs) || len(tables) < 2 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Combination needs at least two distinct tables of this restaurant"})
+		return false
+	}
+
+	capacity := req.Capacity
+	if capacity == 0 {
+		for _, table := range tables {
+			capacity += table.Capacity
+		}
+	}
+
+	combination.Name = req.Name
+	combination.Capacity = capacity
+	combination.MinGuests = req.MinGuests
+	combination.Tables = tables
+	return true
+}
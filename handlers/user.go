@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"net/http"
+	"restaurant-booking/database"
+	"restaurant-booking/models"
+	"restaurant-booking/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type UpdateUserRoleRequest struct {
+	Role         string `json:"role" binding:"required"`
+	RestaurantID *uint  `json:"restaurant_id"`
+}
+
+// Сменить роль пользователя
+func UpdateUserRole(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req UpdateUserRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	if err := services.ValidateUserRole(req.Role, req.RestaurantID); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	if req.Role == models.RoleRestaurantAdmin {
+		var restaurant models.Restaurant
+		if err := database.DB.First(&restaurant, *req.RestaurantID).Error; err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Restaurant not found"})
+			return
+		}
+	}
+
+	var user models.User
+	if err := database.DB.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	if err := services.ChangeUserRole(database.DB, &user, req.Role, req.RestaurantID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user role"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "User role updated successfully",
+		"user":    authUser(user),
+	})
+}
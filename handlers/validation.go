@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"restaurant-booking/services"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+func init() {
+	// В ошибках валидации используем имена полей из JSON, а не из Go-структур
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+			if name == "-" {
+				return ""
+			}
+			return name
+		})
+	}
+}
+
+// Ответить 400 с перечнем ошибок по полям. Понимает ошибки биндинга gin
+// и services.ValidationError; прочие ошибки возвращаются как есть.
+func respondValidationError(c *gin.Context, err error) {
+	var verr *services.ValidationError
+	if errors.As(err, &verr) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "fields": verr.Fields})
+		return
+	}
+
+	var bindErrs validator.ValidationErrors
+	if errors.As(err, &bindErrs) {
+		fields := make([]services.FieldError, 0, len(bindErrs))
+		for _, fe := range bindErrs {
+			fields = append(fields, services.FieldError{Field: fe.Field(), Message: bindingMessage(fe)})
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "fields": fields})
+		return
+	}
+
+	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+}
+
+func bindingMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "min":
+		return fmt.Sprintf("must be at least %s", fe.Param())
+	case "max":
+		return fmt.Sprintf("must be at most %s", fe.Param())
+	case "oneof":
+		return fmt.Sprintf("must be one of: %s", fe.Param())
+	case "email":
+		return "must be a valid email"
+	}
+	return "is invalid"
+}
@@ -0,0 +1,228 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"restaurant-booking/config"
+	"restaurant-booking/database"
+	"restaurant-booking/models"
+	"restaurant-booking/services"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Запрос на постановку в лист ожидания: клиент готов прийти в любое время
+// начала между TimeFrom и TimeTo
+type JoinWaitlistRequest struct {
+	RestaurantID uint   `json:"restaurant_id" binding:"required"`
+	Date         string `json:"date" binding:"required"`
+	TimeFrom     string `json:"time_from" binding:"required"`
+	TimeTo       string `json:"time_to" binding:"required"`
+	Duration     int    `json:"duration" binding:"omitempty,min=1"`
+	Guests       int    `json:"guests" binding:"required,min=1"`
+	Notes        string `json:"notes"`
+}
+
+// Получить записи пользователя в листе ожидания
+func GetUserWaitlist(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	var entries []models.WaitlistEntry
+	if err := database.DB.Where("user_id = ? AND status IN ?", userID, []string{models.WaitlistWaiting, models.WaitlistOffered}).
+		Preload("Restaurant").Order("date, time_from").Find(&entries).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch waitlist"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"waitlist": entries,
+	})
+}
+
+// Встать в лист ожидания
+func JoinWaitlist(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	var req JoinWaitlistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	if err := services.ValidateWaitlistWindow(req.Date, req.TimeFrom, req.TimeTo); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	var restaurant models.Restaurant
+	if err := database.DB.First(&restaurant, req.RestaurantID).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Restaurant not found"})
+		return
+	}
+
+	if err := services.ValidatePartySize(restaurant, req.Guests); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	if req.Duration == 0 {
+		req.Duration = defaultDuration(req.Guests)
+	}
+
+	entry := models.WaitlistEntry{
+		UserID:       userID.(uint),
+		RestaurantID: req.RestaurantID,
+		Date:         req.Date,
+		TimeFrom:     req.TimeFrom,
+		TimeTo:       req.TimeTo,
+		Duration:     req.Duration,
+		Guests:       req.Guests,
+		Notes:        req.Notes,
+		Status:       models.WaitlistWaiting,
+	}
+	if err := database.DB.Create(&entry).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to join waitlist"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Added to waitlist",
+		"entry":   entry,
+	})
+}
+
+// Покинуть лист ожидания
+func LeaveWaitlist(c *gin.Context) {
+	entry, ok := loadUserWaitlistEntry(c)
+	if !ok {
+		return
+	}
+
+	result := database.DB.Model(&models.WaitlistEntry{}).
+		Where("id = ? AND status IN ?", entry.ID, []string{models.WaitlistWaiting, models.WaitlistOffered}).
+		Update("status", models.WaitlistCancelled)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to leave waitlist"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Waitlist entry is no longer active"})
+		return
+	}
+
+	// Отказ от предложения освобождает слот для следующего в очереди
+	if entry.Status == models.WaitlistOffered && entry.OfferedBookingID != nil {
+		if err := services.ReleaseWaitlistOffer(database.DB, entry); err != nil {
+			log.Printf("waitlist: failed to release offer hold of entry %d: %v", entry.ID, err)
+		}
+		offerFreedSlot(models.Booking{ID: *entry.OfferedBookingID})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Removed from waitlist",
+	})
+}
+
+// Принять предложенный слот и создать бронирование
+func ClaimWaitlistOffer(c *gin.Context) {
+	entry, ok := loadUserWaitlistEntry(c)
+	if !ok {
+		return
+	}
+
+	var user models.User
+	if err := database.DB.First(&user, entry.UserID).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	if err := services.CheckEmailVerified(user, config.AppConfig.Account.RequireVerifiedEmail); err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Please verify your email before booking"})
+		return
+	}
+
+	// Бронирование из листа ожидания подчиняется той же политике неявок
+	depositRequired, err := services.CheckNoShowPolicy(user, config.AppConfig.NoShow.Policy, config.AppConfig.NoShow.Threshold)
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Booking is blocked due to repeated no-shows"})
+		return
+	}
+
+	var booking models.Booking
+	err = database.WithSerializableTx(func(tx *gorm.DB) error {
+		var err error
+		booking, err = services.ClaimWaitlistOffer(tx, entry, depositRequired)
+		return err
+	})
+	switch {
+	case errors.Is(err, services.ErrWaitlistNoOffer):
+		c.JSON(http.StatusConflict, gin.H{"error": "No slot has been offered for this waitlist entry"})
+		return
+	case errors.Is(err, services.ErrWaitlistOfferExpired):
+		c.JSON(http.StatusGone, gin.H{"error": "Waitlist offer has expired"})
+		return
+	case err != nil:
+		respondBookingTxError(c, err, "Failed to claim waitlist offer")
+		return
+	}
+
+	if err := database.DB.Preload("Restaurant").Preload("Table").Preload("Tables").First(&booking, booking.ID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load booking"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Booking created from waitlist",
+		"booking": booking,
+	})
+}
+
+// Загрузить запись листа ожидания текущего пользователя по :id
+func loadUserWaitlistEntry(c *gin.Context) (models.WaitlistEntry, bool) {
+	var entry models.WaitlistEntry
+
+	entryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid waitlist entry ID"})
+		return entry, false
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return entry, false
+	}
+
+	if err := database.DB.Where("id = ? AND user_id = ?", entryID, userID).First(&entry).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Waitlist entry not found"})
+		return entry, false
+	}
+	return entry, true
+}
+
+// Предложить освободившийся слот листу ожидания. Ошибка не должна
+// срывать отмену бронирования, поэтому она только логируется.
+func offerFreedSlot(booking models.Booking) {
+	claimTTL := time.Duration(config.AppConfig.Booking.WaitlistClaimMinutes) * time.Minute
+	entry, err := services.OfferFreedSlot(database.DB, booking, claimTTL)
+	if err != nil {
+		log.Printf("waitlist: failed to offer slot of booking %d: %v", booking.ID, err)
+		return
+	}
+	if entry != nil {
+		log.Printf("waitlist: offered slot of booking %d to entry %d until %s",
+			booking.ID, entry.ID, entry.OfferExpiresAt.Format(time.RFC3339))
+	}
+}
@@ -0,0 +1,16 @@
+package jobs
+
+import (
+	"log"
+	"restaurant-booking/database"
+	"restaurant-booking/services"
+)
+
+// Удалить истекшие удержания столиков
+func releaseExpiredHolds() error {
+	released, err := services.ReleaseExpiredHolds(database.DB)
+	if released > 0 {
+		log.Printf("holds: %d expired holds released", released)
+	}
+	return err
+}
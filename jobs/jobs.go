@@ -0,0 +1,35 @@
+package jobs
+
+import (
+	"log"
+	"restaurant-booking/config"
+	"time"
+)
+
+// Запустить все фоновые задачи. Каждая выполняется в своей горутине
+// с периодом из конфигурации.
+func Start() {
+	interval := time.Duration(config.AppConfig.Booking.SweepIntervalSeconds) * time.Second
+
+	every("waitlist", interval, expireWaitlistOffers)
+	every("holds", interval, releaseExpiredHolds)
+	every("no-show", interval, markNoShows)
+	every("refresh-tokens", interval, deleteExpiredRefreshTokens)
+	every("password-resets", interval, deleteExpiredPasswordResets)
+	every("email-verifications", interval, deleteExpiredEmailVerifications)
+}
+
+// Выполнять fn каждые interval до завершения процесса. Ошибки логируются,
+// задача продолжает работать.
+func every(name string, interval time.Duration, fn func() error) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if err := fn(); err != nil {
+				log.Printf("job %s: %v", name, err)
+			}
+		}
+	}()
+}
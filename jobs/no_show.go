@@ -0,0 +1,19 @@
+package jobs
+
+import (
+	"log"
+	"restaurant-booking/config"
+	"restaurant-booking/database"
+	"restaurant-booking/services"
+	"time"
+)
+
+// Отметить неявки по бронированиям, время которых прошло
+func markNoShows() error {
+	grace := time.Duration(config.AppConfig.NoShow.GraceMinutes) * time.Minute
+	marked, err := services.MarkNoShows(database.DB, grace)
+	if marked > 0 {
+		log.Printf("no-show: %d bookings marked as no-show", marked)
+	}
+	return err
+}
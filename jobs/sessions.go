@@ -0,0 +1,34 @@
+package jobs
+
+import (
+	"log"
+	"restaurant-booking/database"
+	"restaurant-booking/services"
+)
+
+// Удалить истекшие refresh-токены
+func deleteExpiredRefreshTokens() error {
+	deleted, err := services.DeleteExpiredRefreshTokens(database.DB)
+	if deleted > 0 {
+		log.Printf("sessions: %d expired refresh tokens deleted", deleted)
+	}
+	return err
+}
+
+// Удалить истекшие токены сброса пароля
+func deleteExpiredPasswordResets() error {
+	deleted, err := services.DeleteExpiredPasswordResets(database.DB)
+	if deleted > 0 {
+		log.Printf("sessions: %d expired password reset tokens deleted", deleted)
+	}
+	return err
+}
+
+// Удалить истекшие токены подтверждения email
+func deleteExpiredEmailVerifications() error {
+	deleted, err := services.DeleteExpiredEmailVerifications(database.DB)
+	if deleted > 0 {
+		log.Printf("sessions: %d expired email verification tokens deleted", deleted)
+	}
+	return err
+}
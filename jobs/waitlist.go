@@ -0,0 +1,19 @@
+package jobs
+
+import (
+	"log"
+	"restaurant-booking/config"
+	"restaurant-booking/database"
+	"restaurant-booking/services"
+	"time"
+)
+
+// Истечь непринятые предложения листа ожидания и передать слоты дальше
+func expireWaitlistOffers() error {
+	claimTTL := time.Duration(config.AppConfig.Booking.WaitlistClaimMinutes) * time.Minute
+	expired, err := services.ExpireWaitlistOffers(database.DB, claimTTL)
+	if expired > 0 {
+		log.Printf("waitlist: %d offers expired", expired)
+	}
+	return err
+}
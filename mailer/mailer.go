@@ -0,0 +1,88 @@
+// Пакет mailer отправляет письма пользователям. Способ доставки
+// подключается через интерфейс Sender; для локальной разработки есть
+// отправители, которые пишут письма в лог или в файлы.
+package mailer
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type Message struct {
+	From    string
+	To      string
+	Subject string
+	Body    string
+}
+
+type Sender interface {
+	Send(msg Message) error
+}
+
+// Отправитель по умолчанию: письма попадают в лог приложения
+type LogSender struct{}
+
+func (LogSender) Send(msg Message) error {
+	log.Printf("mail: to=%s subject=%q\n%s", msg.To, msg.Subject, msg.Body)
+	return nil
+}
+
+// Сохраняет каждое письмо в отдельный .eml файл каталога Dir
+type FileSender struct {
+	Dir string
+}
+
+func (s FileSender) Send(msg Message) error {
+	if err := os.MkdirAll(s.Dir, 0755); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s-%s.eml", time.Now().Format("20060102-150405.000000000"), sanitizeFileName(msg.To))
+	content := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nDate: %s\r\n\r\n%s\r\n",
+		msg.From, msg.To, msg.Subject, time.Now().Format(time.RFC1123Z), msg.Body)
+	return os.WriteFile(filepath.Join(s.Dir, name), []byte(content), 0644)
+}
+
+var (
+	sender Sender = LogSender{}
+	from          = "noreply@localhost"
+)
+
+// Настроить доставку писем: driver "log" или "file" (письма в каталог dir)
+func Setup(driver, dir, sendFrom string) error {
+	switch driver {
+	case "", "log":
+		sender = LogSender{}
+	case "file":
+		sender = FileSender{Dir: dir}
+	default:
+		return fmt.Errorf("unknown mail driver %q", driver)
+	}
+	if sendFrom != "" {
+		from = sendFrom
+	}
+	return nil
+}
+
+// Подключить собственный способ доставки, например SMTP или внешний сервис
+func SetSender(s Sender) {
+	sender = s
+}
+
+// Отправить письмо от адреса по умолчанию
+func Send(to, subject, body string) error {
+	return sender.Send(Message{From: from, To: to, Subject: subject, Body: body})
+}
+
+func sanitizeFileName(value string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ':' || r == ' ' {
+			return '_'
+		}
+		return r
+	}, value)
+}
@@ -5,8 +5,11 @@ import (
 	"os"
 	"restaurant-booking/config"
 	"restaurant-booking/database"
+	"restaurant-booking/jobs"
+	"restaurant-booking/mailer"
 	"restaurant-booking/routes"
 	"restaurant-booking/utils"
+	"time"
 
 	"github.com/sirupsen/logrus"
 )
@@ -29,8 +32,14 @@ func main() {
 	log.Info("Конфигурация загружена")
 
 	utils.SetJWTSecret(config.AppConfig.JWT.Secret)
+	utils.SetAccessTokenTTL(time.Duration(config.AppConfig.JWT.AccessTokenMinutes) * time.Minute)
 	log.Info("JWT секрет установлен")
 
+	if err := mailer.Setup(config.AppConfig.Mail.Driver, config.AppConfig.Mail.Dir, config.AppConfig.Mail.From); err != nil {
+		log.Fatal("Ошибка настройки почты:", err)
+	}
+	log.Info("Отправка почты настроена")
+
 	database.ConnectDB()
 	log.Info("Подключение к базе данных установлено")
 
@@ -40,6 +49,9 @@ func main() {
 	database.SeedData()
 	log.Info("Тестовые данные добавлены")
 
+	jobs.Start()
+	log.Info("Фоновые задачи запущены")
+
 	r := routes.SetupRoutes()
 	log.Info("Маршруты настроены")
 
@@ -5,6 +5,7 @@ import (
 	"strings"
 	"restaurant-booking/database"
 	"restaurant-booking/models"
+	"restaurant-booking/permission"
 
 	"github.com/gin-gonic/gin"
 	"restaurant-booking/utils"
@@ -12,71 +13,83 @@ import (
 
 func AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
-			c.Abort()
+		if !authenticate(c) {
 			return
 		}
+		c.Next()
+	}
+}
 
-		// Проверяем формат "Bearer <token>"
-		tokenParts := strings.Split(authHeader, " ")
-		if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization header format"})
-			c.Abort()
-			return
-		}
+// Проверить токен запроса и поместить пользователя в контекст. При ошибке
+// отвечает 401, прерывает обработку и возвращает false.
+func authenticate(c *gin.Context) bool {
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
+		c.Abort()
+		return false
+	}
 
-		tokenString := tokenParts[1]
-		token, err := utils.ValidateToken(tokenString)
-		if err != nil || !token.Valid {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
-			c.Abort()
-			return
-		}
+	// Проверяем формат "Bearer <token>"
+	tokenParts := strings.Split(authHeader, " ")
+	if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization header format"})
+		c.Abort()
+		return false
+	}
 
-		// Извлекаем user_id из токена и добавляем в контекст
-		userID, err := utils.ExtractUserIDFromToken(tokenString)
-		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
-			c.Abort()
-			return
-		}
+	claims, err := utils.ParseToken(tokenParts[1])
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+		c.Abort()
+		return false
+	}
 
-		c.Set("user_id", userID)
-		c.Next()
+	// После смены роли или сброса пароля версия токенов пользователя
+	// увеличивается, и ранее выданные токены перестают приниматься.
+	// Сверяется только версия, а не весь пользователь.
+	var versions []int
+	if err := database.DB.Model(&models.User{}).Where("id = ?", claims.UserID).
+		Pluck("token_version", &versions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify token"})
+		c.Abort()
+		return false
+	}
+	if len(versions) == 0 || versions[0] != claims.TokenVersion {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Token has been revoked"})
+		c.Abort()
+		return false
 	}
+
+	// Роль и ресторан пользователя берутся из токена
+	c.Set(principalKey, Principal{
+		UserID:       claims.UserID,
+		Username:     claims.Username,
+		Role:         claims.Role,
+		RestaurantID: claims.RestaurantID,
+		TokenVersion: claims.TokenVersion,
+	})
+	c.Set("user_id", claims.UserID)
+	return true
 }
 
-func AdminMiddleware() gin.HandlerFunc {
+// Пропустить запрос, только если у пользователя есть право. Ограничение
+// правом конкретного ресторана проверяет обработчик по загруженному ресурсу.
+func RequirePermission(perm permission.Permission) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Сначала проверяем аутентификацию
-		AuthMiddleware()(c)
-		if c.IsAborted() {
-			return
-		}
-
-		userID, exists := c.Get("user_id")
-		if !exists {
+		principal, ok := CurrentPrincipal(c)
+		if !ok {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
 			c.Abort()
 			return
 		}
 
-		var user models.User
-		if err := database.DB.Where("id = ?", userID).First(&user).Error; err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
-			c.Abort()
-			return
-		}
-
-		if user.Role != "admin" && user.Role != "restaurant_admin" {
+		if !principal.Can(perm) {
 			c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
 			c.Abort()
 			return
 		}
 
-		c.Set("user", user)
 		c.Next()
 	}
-} 
\ No newline at end of file
+}
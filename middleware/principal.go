@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"restaurant-booking/permission"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Ключ контекста gin, под которым хранится Principal
+const principalKey = "principal"
+
+// Аутентифицированный пользователь запроса, восстановленный из утверждений токена
+type Principal struct {
+	UserID       uint
+	Username     string
+	Role         string
+	RestaurantID *uint
+	TokenVersion int
+}
+
+// Есть ли у пользователя право без учета ресторана
+func (p Principal) Can(perm permission.Permission) bool {
+	return permission.Granted(p.Role, perm)
+}
+
+// Ресторан, которым ограничены права пользователя. scoped == false -
+// права действуют на все рестораны. Администратор ресторана без
+// привязки получает id 0, которому не соответствует ни один ресторан.
+func (p Principal) RestaurantScope() (id uint, scoped bool) {
+	if permission.GlobalScope(p.Role) {
+		return 0, false
+	}
+	if p.RestaurantID == nil {
+		return 0, true
+	}
+	return *p.RestaurantID, true
+}
+
+// Есть ли у пользователя право в отношении ресторана
+func (p Principal) CanInRestaurant(perm permission.Permission, restaurantID uint) bool {
+	if !p.Can(perm) {
+		return false
+	}
+	id, scoped := p.RestaurantScope()
+	return !scoped || id == restaurantID
+}
+
+// Получить пользователя запроса, установленного AuthMiddleware
+func CurrentPrincipal(c *gin.Context) (Principal, bool) {
+	value, exists := c.Get(principalKey)
+	if !exists {
+		return Principal{}, false
+	}
+	principal, ok := value.(Principal)
+	return principal, ok
+}
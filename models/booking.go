@@ -1,6 +1,8 @@
 package models
 
 import (
+	"restaurant-booking/bookingstatus"
+	"restaurant-booking/utils"
 	"time"
 
 	"gorm.io/gorm"
@@ -9,14 +11,21 @@ import (
 type Booking struct {
 	ID         uint           `json:"id" gorm:"primaryKey"`
 	UserID     uint           `json:"user_id" gorm:"not null"`
-	TableID    uint           `json:"table_id" gorm:"not null"`
+	TableID    uint           `json:"table_id" gorm:"not null"` // основной столик; все занятые столики - в Tables
+	TableCombinationID *uint  `json:"table_combination_id,omitempty"`
+	SeriesID   *uint          `json:"series_id,omitempty" gorm:"index"` // серия повторяющихся бронирований
 	RestaurantID uint          `json:"restaurant_id" gorm:"not null"`
 	Date       string         `json:"date" gorm:"not null"`
 	Time       string         `json:"time" gorm:"not null"`
 	Duration   int            `json:"duration" gorm:"default:120"` // в минутах
 	Guests     int            `json:"guests" gorm:"not null"`
-	Status     string         `json:"status" gorm:"default:'pending'"` // pending, confirmed, cancelled, completed
+	Status     bookingstatus.Status `json:"status" gorm:"default:'pending'"`
 	Notes      string         `json:"notes"`
+	DepositRequired bool      `json:"deposit_required"` // клиент с повторными неявками вносит депозит
+	LateCancellation bool     `json:"late_cancellation"` // отменено после окончания бесплатной отмены
+	CancelledAt *time.Time    `json:"cancelled_at,omitempty"`
+	StartsAt   time.Time      `json:"starts_at" gorm:"index"`
+	EndsAt     time.Time      `json:"ends_at" gorm:"index"`
 	CreatedAt  time.Time      `json:"created_at"`
 	UpdatedAt  time.Time      `json:"updated_at"`
 	DeletedAt  gorm.DeletedAt `json:"-" gorm:"index"`
@@ -24,5 +33,20 @@ type Booking struct {
 	// Связи
 	User       User       `json:"user,omitempty" gorm:"foreignKey:UserID"`
 	Table      Table      `json:"table,omitempty" gorm:"foreignKey:TableID"`
+	Tables     []Table    `json:"tables,omitempty" gorm:"many2many:booking_tables"`
 	Restaurant Restaurant `json:"restaurant,omitempty" gorm:"foreignKey:RestaurantID"`
-} 
\ No newline at end of file
+}
+
+// Длительность бронирования по умолчанию, в минутах
+const DefaultBookingDuration = 120
+
+// Пересчитать StartsAt/EndsAt по Date, Time и Duration
+func (b *Booking) SetWindow() error {
+	start, end, err := utils.ParseBookingWindow(b.Date, b.Time, b.Duration)
+	if err != nil {
+		return err
+	}
+	b.StartsAt = start
+	b.EndsAt = end
+	return nil
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Частота повторения серии бронирований
+const (
+	SeriesWeekly  = "weekly"
+	SeriesMonthly = "monthly"
+)
+
+// Статусы серии
+const (
+	SeriesActive    = "active"
+	SeriesCancelled = "cancelled"
+)
+
+// Серия повторяющихся бронирований одного столика, например каждую пятницу.
+// Правило похоже на RRULE: Frequency и Interval задают шаг, серия заканчивается
+// датой Until или после Count повторений. Каждое повторение - отдельный Booking.
+type BookingSeries struct {
+	ID                 uint           `json:"id" gorm:"primaryKey"`
+	UserID             uint           `json:"user_id" gorm:"not null;index"`
+	RestaurantID       uint           `json:"restaurant_id" gorm:"not null"`
+	TableID            uint           `json:"table_id" gorm:"not null"`
+	TableCombinationID *uint          `json:"table_combination_id,omitempty"`
+	Frequency          string         `json:"frequency" gorm:"not null"`
+	Interval           int            `json:"interval" gorm:"default:1"`
+	StartDate          string         `json:"start_date" gorm:"not null"`
+	Until              string         `json:"until,omitempty"`
+	Count              int            `json:"count,omitempty"`
+	Time               string         `json:"time" gorm:"not null"`
+	Duration           int            `json:"duration"`
+	Guests             int            `json:"guests" gorm:"not null"`
+	Notes              string         `json:"notes"`
+	Status             string         `json:"status" gorm:"default:'active'"`
+	CreatedAt          time.Time      `json:"created_at"`
+	UpdatedAt          time.Time      `json:"updated_at"`
+	DeletedAt          gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// Связи
+	Bookings   []Booking  `json:"bookings,omitempty" gorm:"foreignKey:SeriesID"`
+	Restaurant Restaurant `json:"restaurant,omitempty" gorm:"foreignKey:RestaurantID"`
+}
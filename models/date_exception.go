@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+)
+
+// Типы исключений из расписания на конкретную дату
+const (
+	DateExceptionClosed = "closed" // ресторан закрыт весь день
+	DateExceptionHours  = "hours"  // особые часы работы вместо обычного расписания
+	DateExceptionTables = "tables" // отдельные столики недоступны (например, банкет)
+)
+
+// Исключение из расписания ресторана на дату: праздник, частное мероприятие и т.п.
+type DateException struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	RestaurantID uint      `json:"restaurant_id" gorm:"not null;index"`
+	Date         string    `json:"date" gorm:"not null;index"`
+	Type         string    `json:"type" gorm:"not null"`
+	OpensAt      string    `json:"opens_at,omitempty"`
+	ClosesAt     string    `json:"closes_at,omitempty"`
+	Reason       string    `json:"reason"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+
+	// Связи
+	Tables []Table `json:"tables,omitempty" gorm:"many2many:date_exception_tables"`
+}
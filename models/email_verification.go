@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// Одноразовый токен подтверждения email. Хранится только хеш токена;
+// время создания последнего токена ограничивает частоту повторной отправки.
+type EmailVerificationToken struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	UserID    uint       `json:"user_id" gorm:"not null;index"`
+	TokenHash string     `json:"-" gorm:"uniqueIndex;not null"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null;index"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+)
+
+// Интервал работы ресторана в день недели. Если ClosesAt не позже OpensAt,
+// смена заканчивается на следующий день. В один день может быть несколько
+// интервалов (например, обед и ужин с перерывом).
+type OpeningHours struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	RestaurantID uint      `json:"restaurant_id" gorm:"not null;index"`
+	Weekday      int       `json:"weekday" gorm:"not null"` // 0 - воскресенье, 6 - суббота
+	OpensAt      string    `json:"opens_at" gorm:"not null"`
+	ClosesAt     string    `json:"closes_at" gorm:"not null"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// Refresh-токен сессии. Хранится только хеш токена. При каждом обновлении
+// токен заменяется новым из того же семейства (FamilyID), а старый помечается
+// использованным; повторное предъявление использованного токена означает
+// кражу, и все семейство отзывается.
+type RefreshToken struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	UserID    uint       `json:"user_id" gorm:"not null;index"`
+	TokenHash string     `json:"-" gorm:"uniqueIndex;not null"`
+	FamilyID  string     `json:"family_id" gorm:"not null;index"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null;index"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
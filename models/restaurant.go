@@ -16,10 +16,21 @@ type Restaurant struct {
 	Website     string         `json:"website"`
 	OpeningTime string         `json:"opening_time"`
 	ClosingTime string         `json:"closing_time"`
+	MinPartySize int           `json:"min_party_size" gorm:"default:1"`
+	MaxPartySize int           `json:"max_party_size"` // 0 - без ограничения
+	AssignmentStrategy string  `json:"assignment_strategy" gorm:"default:'best_fit'"` // стратегия автоподбора столика
+	FreeCancellationHours     int `json:"free_cancellation_hours" gorm:"default:24"` // бесплатная отмена не позже чем за столько часов; 0 - до запрета отмены
+	CancellationCutoffMinutes int `json:"cancellation_cutoff_minutes"`                // отмена запрещена позже чем за столько минут до начала
+	MinLeadMinutes            int `json:"min_lead_minutes"`                           // бронировать не позже чем за столько минут до начала
+	MaxAdvanceDays            int `json:"max_advance_days"`                           // на сколько дней вперед можно бронировать, 0 - без ограничения
+	TurnoverBufferMinutes     int `json:"turnover_buffer_minutes"`                    // уборка столика после каждого бронирования
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
 	
 	// Связи
-	Tables []Table `json:"tables,omitempty" gorm:"foreignKey:RestaurantID"`
+	Tables         []Table         `json:"tables,omitempty" gorm:"foreignKey:RestaurantID"`
+	OpeningHours   []OpeningHours  `json:"opening_hours,omitempty" gorm:"foreignKey:RestaurantID"`
+	DateExceptions []DateException `json:"date_exceptions,omitempty" gorm:"foreignKey:RestaurantID"`
+	TableCombinations []TableCombination `json:"table_combinations,omitempty" gorm:"foreignKey:RestaurantID"`
 } 
\ No newline at end of file
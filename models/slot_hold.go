@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// Временное удержание столиков на время оформления бронирования.
+// Пока ExpiresAt не наступил, столики считаются занятыми для всех,
+// а владелец может создать бронирование, предъявив Token.
+type SlotHold struct {
+	ID                 uint      `json:"id" gorm:"primaryKey"`
+	Token              string    `json:"token" gorm:"uniqueIndex;not null"`
+	UserID             uint      `json:"user_id" gorm:"not null;index"`
+	RestaurantID       uint      `json:"restaurant_id" gorm:"not null"`
+	TableID            uint      `json:"table_id" gorm:"not null"` // основной столик; все удерживаемые - в Tables
+	TableCombinationID *uint     `json:"table_combination_id,omitempty"`
+	Date               string    `json:"date" gorm:"not null"`
+	Time               string    `json:"time" gorm:"not null"`
+	Duration           int       `json:"duration"`
+	Guests             int       `json:"guests" gorm:"not null"`
+	StartsAt           time.Time `json:"starts_at" gorm:"index"`
+	EndsAt             time.Time `json:"ends_at" gorm:"index"`
+	ExpiresAt          time.Time `json:"expires_at" gorm:"not null;index"`
+	CreatedAt          time.Time `json:"created_at"`
+
+	// Связи
+	Tables []Table `json:"tables,omitempty" gorm:"many2many:slot_hold_tables"`
+}
@@ -6,13 +6,22 @@ import (
 	"gorm.io/gorm"
 )
 
+// Статус столика отражает только то, обслуживается ли он;
+// занятость на конкретное время определяется бронированиями
+const (
+	TableStatusAvailable    = "available"
+	TableStatusOutOfService = "out_of_service"
+)
+
 type Table struct {
 	ID           uint           `json:"id" gorm:"primaryKey"`
 	RestaurantID uint           `json:"restaurant_id" gorm:"not null"`
 	Number       int            `json:"number" gorm:"not null"`
 	Capacity     int            `json:"capacity" gorm:"not null"`
-	Status       string         `json:"status" gorm:"default:'available'"`
+	MinGuests    int            `json:"min_guests"` // минимальная загрузка, 0 - без ограничения
+	Status       string         `json:"status" gorm:"default:'available'"` // available, out_of_service
 	Location     string         `json:"location"`
+	TurnoverBufferMinutes *int  `json:"turnover_buffer_minutes,omitempty"` // своя уборка вместо буфера ресторана
 	CreatedAt    time.Time      `json:"created_at"`
 	UpdatedAt    time.Time      `json:"updated_at"`
 	DeletedAt    gorm.DeletedAt `json:"-" gorm:"index"`
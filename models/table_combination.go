@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+)
+
+// Набор столиков, которые можно сдвинуть для большой компании.
+// Capacity - вместимость составного стола, она может отличаться от суммы.
+type TableCombination struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	RestaurantID uint      `json:"restaurant_id" gorm:"not null;index"`
+	Name         string    `json:"name"`
+	Capacity     int       `json:"capacity" gorm:"not null"`
+	MinGuests    int       `json:"min_guests"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+
+	// Связи
+	Tables []Table `json:"tables,omitempty" gorm:"many2many:table_combination_tables"`
+}
@@ -7,18 +7,27 @@ import (
 )
 
 type User struct {
-	ID           uint           `json:"id" gorm:"primaryKey"`
-	Username     string         `json:"username" gorm:"uniqueIndex;not null"`
-	Email        string         `json:"email" gorm:"uniqueIndex;not null"`
-	Password     string         `json:"-" gorm:"not null"`
-	FirstName    string         `json:"first_name"`
-	LastName     string         `json:"last_name"`
-	Phone        string         `json:"phone"`
-	Role         string         `json:"role" gorm:"default:'customer'"`
-	RestaurantID *uint          `json:"restaurant_id"`
-	CreatedAt    time.Time      `json:"created_at"`
-	UpdatedAt    time.Time      `json:"updated_at"`
-	DeletedAt    gorm.DeletedAt `json:"-" gorm:"index"`
-	
+	ID              uint           `json:"id" gorm:"primaryKey"`
+	Username        string         `json:"username" gorm:"uniqueIndex;not null"`
+	Email           string         `json:"email" gorm:"uniqueIndex;not null"`
+	Password        string         `json:"-" gorm:"not null"`
+	FirstName       string         `json:"first_name"`
+	LastName        string         `json:"last_name"`
+	Phone           string         `json:"phone"`
+	Role            string         `json:"role" gorm:"default:'customer'"`
+	RestaurantID    *uint          `json:"restaurant_id"`
+	NoShowCount     int            `json:"no_show_count" gorm:"default:0"`
+	TokenVersion    int            `json:"-" gorm:"default:0"` // увеличивается при смене роли и сбросе пароля, отзывая выданные токены
+	EmailVerifiedAt *time.Time     `json:"email_verified_at"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt `json:"-" gorm:"index"`
+
 	Restaurant Restaurant `json:"restaurant,omitempty" gorm:"foreignKey:RestaurantID"`
-} 
\ No newline at end of file
+}
+
+const (
+	RoleCustomer        = "customer"
+	RoleRestaurantAdmin = "restaurant_admin" // управляет только рестораном RestaurantID
+	RoleAdmin           = "admin"
+)
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Статусы записи в листе ожидания
+const (
+	WaitlistWaiting   = "waiting"   // ждет освободившегося слота
+	WaitlistOffered   = "offered"   // слот предложен, ждем подтверждения до OfferExpiresAt
+	WaitlistClaimed   = "claimed"   // предложение принято, создано бронирование
+	WaitlistExpired   = "expired"   // предложение не принято вовремя
+	WaitlistCancelled = "cancelled" // клиент покинул лист ожидания
+)
+
+// Запись в листе ожидания: клиент готов прийти в окно TimeFrom-TimeTo
+type WaitlistEntry struct {
+	ID               uint           `json:"id" gorm:"primaryKey"`
+	UserID           uint           `json:"user_id" gorm:"not null;index"`
+	RestaurantID     uint           `json:"restaurant_id" gorm:"not null;index"`
+	Date             string         `json:"date" gorm:"not null"`
+	TimeFrom         string         `json:"time_from" gorm:"not null"`
+	TimeTo           string         `json:"time_to" gorm:"not null"`
+	Duration         int            `json:"duration"`
+	Guests           int            `json:"guests" gorm:"not null"`
+	Notes            string         `json:"notes"`
+	Status           string         `json:"status" gorm:"default:'waiting';index"`
+	OfferedBookingID *uint          `json:"offered_booking_id,omitempty"` // отмененное бронирование, чей слот предложен
+	OfferedTime      string         `json:"offered_time,omitempty"`
+	OfferExpiresAt   *time.Time     `json:"offer_expires_at,omitempty"`
+	OfferHoldID      *uint          `json:"-"`                    // удержание столиков на время предложения
+	BookingID        *uint          `json:"booking_id,omitempty"` // бронирование, созданное при подтверждении
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// Связи
+	Restaurant Restaurant `json:"restaurant,omitempty" gorm:"foreignKey:RestaurantID"`
+}
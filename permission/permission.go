@@ -0,0 +1,44 @@
+// Пакет permission описывает права ролей. Обработчики проверяют права,
+// а не имена ролей; набор прав роли задается в одном месте.
+package permission
+
+import "restaurant-booking/models"
+
+type Permission string
+
+const (
+	RestaurantCreate    Permission = "restaurant.create"
+	RestaurantDelete    Permission = "restaurant.delete"
+	RestaurantManage    Permission = "restaurant.manage" // настройки, расписание и исключения ресторана
+	TableManage         Permission = "table.manage"      // столики и их комбинации
+	BookingView         Permission = "booking.view"
+	BookingStatusUpdate Permission = "booking.status.update"
+	UserRoleManage      Permission = "user.role.manage"
+)
+
+// Права ролей. Права администратора ресторана действуют только
+// в пределах его ресторана, см. GlobalScope.
+var grants = map[string][]Permission{
+	models.RoleAdmin: {
+		RestaurantCreate, RestaurantDelete, RestaurantManage, TableManage,
+		BookingView, BookingStatusUpdate, UserRoleManage,
+	},
+	models.RoleRestaurantAdmin: {
+		RestaurantManage, TableManage, BookingView, BookingStatusUpdate,
+	},
+}
+
+// Есть ли у роли право
+func Granted(role string, p Permission) bool {
+	for _, granted := range grants[role] {
+		if granted == p {
+			return true
+		}
+	}
+	return false
+}
+
+// Действуют ли права роли на все рестораны, а не только на собственный
+func GlobalScope(role string) bool {
+	return role == models.RoleAdmin
+}
@@ -3,6 +3,7 @@ package routes
 import (
 	"restaurant-booking/handlers"
 	"restaurant-booking/middleware"
+	"restaurant-booking/permission"
 
 	"github.com/gin-gonic/gin"
 )
@@ -16,36 +17,90 @@ func SetupRoutes() *gin.Engine {
 		// Аутентификация
 		public.POST("/register", handlers.Register)
 		public.POST("/login", handlers.Login)
+		public.POST("/token/refresh", handlers.RefreshToken)
+		public.POST("/logout", handlers.Logout)
+		public.POST("/password/reset", handlers.RequestPasswordReset)
+		public.POST("/password/reset/confirm", handlers.ConfirmPasswordReset)
+		public.POST("/email/verify", handlers.VerifyEmail)
 		
 		// Рестораны (публичные)
 		public.GET("/restaurants", handlers.GetRestaurants)
 		public.GET("/restaurants/id/:id", handlers.GetRestaurant)
 		public.GET("/restaurants/:restaurant_id/tables/available", handlers.GetAvailableTables)
+		public.GET("/restaurants/:restaurant_id/slots", handlers.GetAvailableSlots)
 	}
 
 	// Защищенные маршруты
 	protected := r.Group("/api")
 	protected.Use(middleware.AuthMiddleware())
 	{
+		// Подтверждение email
+		protected.POST("/email/verify/resend", handlers.ResendVerificationEmail)
+
 		// Бронирования
 		protected.GET("/bookings", handlers.GetUserBookings)
 		protected.GET("/bookings/id/:id", handlers.GetBooking)
 		protected.POST("/bookings", handlers.CreateBooking)
 		protected.PUT("/bookings/id/:id", handlers.UpdateBooking)
 		protected.DELETE("/bookings/id/:id", handlers.CancelBooking)
+
+		// Повторяющиеся бронирования
+		protected.GET("/bookings/series", handlers.GetUserBookingSeries)
+		protected.GET("/bookings/series/:id", handlers.GetBookingSeries)
+		protected.POST("/bookings/series", handlers.CreateBookingSeries)
+		protected.DELETE("/bookings/series/:id", handlers.CancelBookingSeries)
+
+		// Удержание столика на время оформления
+		protected.POST("/holds", handlers.CreateHold)
+		protected.DELETE("/holds/:token", handlers.ReleaseHold)
+
+		// Лист ожидания
+		protected.GET("/waitlist", handlers.GetUserWaitlist)
+		protected.POST("/waitlist", handlers.JoinWaitlist)
+		protected.DELETE("/waitlist/id/:id", handlers.LeaveWaitlist)
+		protected.POST("/waitlist/id/:id/claim", handlers.ClaimWaitlistOffer)
 	}
 
-	// Админские маршруты
+	// Админские маршруты. Группа только аутентифицирует пользователя: каждый
+	// маршрут требует права через RequirePermission, ограничение рестораном
+	// пользователя проверяется в обработчике.
 	admin := r.Group("/api/admin")
-	admin.Use(middleware.AdminMiddleware())
+	admin.Use(middleware.AuthMiddleware())
 	{
 		// Управление ресторанами
-		admin.POST("/restaurants", handlers.CreateRestaurant)
-		admin.PUT("/restaurants/id/:id", handlers.UpdateRestaurant)
-		admin.DELETE("/restaurants/id/:id", handlers.DeleteRestaurant)
+		admin.POST("/restaurants", middleware.RequirePermission(permission.RestaurantCreate), handlers.CreateRestaurant)
+		admin.PUT("/restaurants/id/:id", middleware.RequirePermission(permission.RestaurantManage), handlers.UpdateRestaurant)
+		admin.DELETE("/restaurants/id/:id", middleware.RequirePermission(permission.RestaurantDelete), handlers.DeleteRestaurant)
+
+		// Расписание работы ресторана
+		admin.GET("/restaurants/id/:id/hours", middleware.RequirePermission(permission.RestaurantManage), handlers.GetOpeningHours)
+		admin.POST("/restaurants/id/:id/hours", middleware.RequirePermission(permission.RestaurantManage), handlers.CreateOpeningHours)
+		admin.PUT("/restaurants/id/:id/hours/:hours_id", middleware.RequirePermission(permission.RestaurantManage), handlers.UpdateOpeningHours)
+		admin.DELETE("/restaurants/id/:id/hours/:hours_id", middleware.RequirePermission(permission.RestaurantManage), handlers.DeleteOpeningHours)
+
+		// Праздники и особые даты
+		admin.GET("/restaurants/id/:id/exceptions", middleware.RequirePermission(permission.RestaurantManage), handlers.GetDateExceptions)
+		admin.POST("/restaurants/id/:id/exceptions", middleware.RequirePermission(permission.RestaurantManage), handlers.CreateDateException)
+		admin.PUT("/restaurants/id/:id/exceptions/:exception_id", middleware.RequirePermission(permission.RestaurantManage), handlers.UpdateDateException)
+		admin.DELETE("/restaurants/id/:id/exceptions/:exception_id", middleware.RequirePermission(permission.RestaurantManage), handlers.DeleteDateException)
+
+		// Столики ресторана
+		admin.GET("/restaurants/id/:id/tables", middleware.RequirePermission(permission.TableManage), handlers.GetTables)
+		admin.POST("/restaurants/id/:id/tables", middleware.RequirePermission(permission.TableManage), handlers.CreateTable)
+		admin.PUT("/restaurants/id/:id/tables/:table_id", middleware.RequirePermission(permission.TableManage), handlers.UpdateTable)
+		admin.DELETE("/restaurants/id/:id/tables/:table_id", middleware.RequirePermission(permission.TableManage), handlers.DeleteTable)
+
+		// Комбинации столиков для больших компаний
+		admin.GET("/restaurants/id/:id/combinations", middleware.RequirePermission(permission.TableManage), handlers.GetTableCombinations)
+		admin.POST("/restaurants/id/:id/combinations", middleware.RequirePermission(permission.TableManage), handlers.CreateTableCombination)
+		admin.PUT("/restaurants/id/:id/combinations/:combination_id", middleware.RequirePermission(permission.TableManage), handlers.UpdateTableCombination)
+		admin.DELETE("/restaurants/id/:id/combinations/:combination_id", middleware.RequirePermission(permission.TableManage), handlers.DeleteTableCombination)
 		
-		admin.GET("/bookings", handlers.GetRestaurantBookings)
-		admin.PUT("/bookings/id/:id/status", handlers.UpdateBookingStatus)
+		admin.GET("/bookings", middleware.RequirePermission(permission.BookingView), handlers.GetRestaurantBookings)
+		admin.PUT("/bookings/id/:id/status", middleware.RequirePermission(permission.BookingStatusUpdate), handlers.UpdateBookingStatus)
+
+		// Роли пользователей
+		admin.PUT("/users/id/:id/role", middleware.RequirePermission(permission.UserRoleManage), handlers.UpdateUserRole)
 	}
 
 	return r
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"errors"
+	"restaurant-booking/models"
+	"sort"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const DefaultAssignmentStrategy = "best_fit"
+
+var ErrNoTableAvailable = errors.New("no table available for this party and time")
+
+// Параметры автоматического подбора столика
+type AssignmentRequest struct {
+	Guests   int
+	Location string // предпочтительная зона, необязательно
+}
+
+// Стратегия выбора столика среди свободных кандидатов. Кандидаты уже
+// проверены на вместимость и занятость; стратегия решает только, какой взять.
+type TableAssigner interface {
+	Assign(candidates []models.Table, req AssignmentRequest) (models.Table, bool)
+}
+
+// Наименьший подходящий столик, чтобы не занимать большие столы малыми компаниями;
+// при равной вместимости предпочитается запрошенная зона
+type BestFitAssigner struct{}
+
+func (BestFitAssigner) Assign(candidates []models.Table, req AssignmentRequest) (models.Table, bool) {
+	if len(candidates) == 0 {
+		return models.Table{}, false
+	}
+	sorted := append([]models.Table(nil), candidates...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Capacity != sorted[j].Capacity {
+			return sorted[i].Capacity < sorted[j].Capacity
+		}
+		return matchesLocation(sorted[i], req) && !matchesLocation(sorted[j], req)
+	})
+	return sorted[0], true
+}
+
+// Сначала столики в запрошенной зоне, среди них - наименьший подходящий
+type LocationFirstAssigner struct{}
+
+func (LocationFirstAssigner) Assign(candidates []models.Table, req AssignmentRequest) (models.Table, bool) {
+	var preferred []models.Table
+	for _, table := range candidates {
+		if matchesLocation(table, req) {
+			preferred = append(preferred, table)
+		}
+	}
+	if table, ok := (BestFitAssigner{}).Assign(preferred, req); ok {
+		return table, true
+	}
+	return BestFitAssigner{}.Assign(candidates, req)
+}
+
+func matchesLocation(table models.Table, req AssignmentRequest) bool {
+	return req.Location != "" && table.Location == req.Location
+}
+
+var (
+	assignersMu sync.RWMutex
+	assigners   = map[string]TableAssigner{
+		DefaultAssignmentStrategy: BestFitAssigner{},
+		"location_first":          LocationFirstAssigner{},
+	}
+)
+
+// Зарегистрировать стратегию, которую рестораны могут указать в assignment_strategy
+func RegisterAssigner(name string, assigner TableAssigner) {
+	assignersMu.Lock()
+	defer assignersMu.Unlock()
+	assigners[name] = assigner
+}
+
+// Зарегистрирована ли стратегия с таким именем
+func HasAssigner(name string) bool {
+	assignersMu.RLock()
+	defer assignersMu.RUnlock()
+	_, ok := assigners[name]
+	return ok
+}
+
+// Стратегия ресторана; неизвестная или пустая заменяется стратегией по умолчанию
+func AssignerFor(restaurant models.Restaurant) TableAssigner {
+	assignersMu.RLock()
+	defer assignersMu.RUnlock()
+	if assigner, ok := assigners[restaurant.AssignmentStrategy]; ok {
+		return assigner
+	}
+	return assigners[DefaultAssignmentStrategy]
+}
+
+// Результат подбора: один столик или комбинация сдвинутых столиков
+type Assignment struct {
+	Tables      []models.Table
+	Combination *models.TableCombination
+}
+
+// Подобрать свободный столик на интервал [start, end) по стратегии ресторана.
+// Если ни один столик не подходит, берется наименьшая свободная комбинация.
+func AssignTable(db *gorm.DB, restaurant models.Restaurant, start, end time.Time, req AssignmentRequest) (Assignment, error) {
+	query := AvailabilityQuery{
+		RestaurantID: restaurant.ID,
+		Start:        start,
+		End:          end,
+		Guests:       req.Guests,
+	}
+
+	candidates, err := FindFreeTables(db, query)
+	if err != nil {
+		return Assignment{}, err
+	}
+	if table, ok := AssignerFor(restaurant).Assign(candidates, req); ok {
+		return Assignment{Tables: []models.Table{table}}, nil
+	}
+
+	combinations, err := FindFreeCombinations(db, query)
+	if err != nil {
+		return Assignment{}, err
+	}
+	if len(combinations) == 0 {
+		return Assignment{}, ErrNoTableAvailable
+	}
+	return Assignment{Tables: combinations[0].Tables, Combination: &combinations[0]}, nil
+}
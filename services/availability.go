@@ -0,0 +1,135 @@
+package services
+
+import (
+	"errors"
+	"restaurant-booking/bookingstatus"
+	"restaurant-booking/models"
+	"restaurant-booking/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrTableUnavailable = errors.New("table is already booked for this time")
+	ErrTableBlocked     = errors.New("table is not available on this date")
+)
+
+// Параметры поиска свободных столиков
+type AvailabilityQuery struct {
+	RestaurantID     uint
+	Start            time.Time
+	End              time.Time
+	Guests           int
+	ExcludeBookingID uint
+}
+
+// Получить столики ресторана, которые в работе, вмещают гостей и свободны на интервал.
+// Столики под действующими удержаниями свободными не считаются.
+func FindFreeTables(db *gorm.DB, q AvailabilityQuery) ([]models.Table, error) {
+	var tables []models.Table
+	err := db.Where("restaurant_id = ? AND capacity >= ? AND min_guests <= ? AND status = ?",
+		q.RestaurantID, q.Guests, q.Guests, models.TableStatusAvailable).
+		Where("id NOT IN (?)", busyTableIDs(db, q.Start, q.End, q.ExcludeBookingID)).
+		Where("id NOT IN (?)", heldTableIDs(db, q.Start, q.End)).
+		Where("id NOT IN (?)", blockedTableIDs(db, q.Start, q.End)).
+		Order("capacity, number").
+		Find(&tables).Error
+	return tables, err
+}
+
+// Получить комбинации столиков ресторана, которые вмещают гостей и все столики
+// которых в работе и свободны на интервал
+func FindFreeCombinations(db *gorm.DB, q AvailabilityQuery) ([]models.TableCombination, error) {
+	unavailable := db.Unscoped().Model(&models.Table{}).Select("id").
+		Where("status <> ? OR deleted_at IS NOT NULL OR id IN (?) OR id IN (?) OR id IN (?)",
+			models.TableStatusAvailable,
+			busyTableIDs(db, q.Start, q.End, q.ExcludeBookingID),
+			heldTableIDs(db, q.Start, q.End),
+			blockedTableIDs(db, q.Start, q.End))
+
+	var combinations []models.TableCombination
+	err := db.Where("restaurant_id = ? AND capacity >= ? AND min_guests <= ?", q.RestaurantID, q.Guests, q.Guests).
+		Where(`NOT EXISTS (SELECT 1 FROM table_combination_tables tct
+			WHERE tct.table_combination_id = table_combinations.id AND tct.table_id IN (?))`, unavailable).
+		Preload("Tables").
+		Order("capacity, id").
+		Find(&combinations).Error
+	return combinations, err
+}
+
+// Вернуть ErrTableBlocked, если столик закрыт исключением на дату,
+// ErrTableUnavailable, если он занят на интервал [start, end),
+// или ErrTableHeld, если его удерживает другой клиент
+func EnsureTableFree(db *gorm.DB, tableID uint, start, end time.Time, excludeBookingID uint) error {
+	return EnsureTablesFree(db, []uint{tableID}, start, end, excludeBookingID)
+}
+
+// То же, что EnsureTableFree, для всех столиков бронирования сразу
+func EnsureTablesFree(db *gorm.DB, tableIDs []uint, start, end time.Time, excludeBookingID uint) error {
+	var blocked int64
+	if err := blockedTableIDs(db, start, end).Where("date_exception_tables.table_id IN ?", tableIDs).Count(&blocked).Error; err != nil {
+		return err
+	}
+	if blocked > 0 {
+		return ErrTableBlocked
+	}
+
+	var busy int64
+	if err := busyTableIDs(db, start, end, excludeBookingID).Where("booking_tables.table_id IN ?", tableIDs).Count(&busy).Error; err != nil {
+		return err
+	}
+	if busy > 0 {
+		return ErrTableUnavailable
+	}
+
+	var held int64
+	if err := heldTableIDs(db, start, end).Where("slot_hold_tables.table_id IN ?", tableIDs).Count(&held).Error; err != nil {
+		return err
+	}
+	if held > 0 {
+		return ErrTableHeld
+	}
+	return nil
+}
+
+// Идентификаторы столиков
+func TableIDs(tables []models.Table) []uint {
+	ids := make([]uint, 0, len(tables))
+	for _, table := range tables {
+		ids = append(ids, table.ID)
+	}
+	return ids
+}
+
+// Время уборки столика после бронирования: собственное значение столика
+// или буфер ресторана. Выражение для запросов, присоединяющих tables и restaurants.
+const turnoverBufferSQL = "COALESCE(tables.turnover_buffer_minutes, restaurants.turnover_buffer_minutes, 0) * INTERVAL '1 minute'"
+
+// Подзапрос идентификаторов столиков, занятых активными бронированиями на интервал.
+// Бронирование может занимать несколько столиков, поэтому они берутся из booking_tables.
+// После каждого бронирования столик занят еще на время уборки, поэтому новое
+// бронирование тоже должно закончиться за время уборки до начала следующего.
+func busyTableIDs(db *gorm.DB, start, end time.Time, excludeBookingID uint) *gorm.DB {
+	return db.Table("booking_tables").Select("booking_tables.table_id").
+		Joins("JOIN bookings ON bookings.id = booking_tables.booking_id").
+		Joins("JOIN tables ON tables.id = booking_tables.table_id").
+		Joins("JOIN restaurants ON restaurants.id = tables.restaurant_id").
+		Where("bookings.deleted_at IS NULL AND bookings.id <> ? AND bookings.status IN ?", excludeBookingID, bookingstatus.Active).
+		Where("bookings.starts_at < CAST(? AS timestamptz) + "+turnoverBufferSQL+" AND bookings.ends_at + "+turnoverBufferSQL+" > ?", end, start)
+}
+
+// Подзапрос идентификаторов столиков, закрытых исключениями на даты, которые затрагивает интервал
+func blockedTableIDs(db *gorm.DB, start, end time.Time) *gorm.DB {
+	var dates []string
+	for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+		dates = append(dates, day.Format(utils.DateLayout))
+	}
+	if last := end.Add(-time.Nanosecond).Format(utils.DateLayout); dates[len(dates)-1] != last {
+		dates = append(dates, last)
+	}
+
+	return db.Table("date_exception_tables").Select("date_exception_tables.table_id").
+		Joins("JOIN date_exceptions ON date_exceptions.id = date_exception_tables.date_exception_id").
+		Where("date_exceptions.type = ? AND date_exceptions.date IN ?", models.DateExceptionTables, dates)
+}
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"fmt"
+	"restaurant-booking/bookingstatus"
+	"restaurant-booking/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Перевести бронирование в новый статус с проверкой допустимости перехода.
+// Обновление условно по текущему статусу, поэтому параллельное изменение
+// также приводит к ErrInvalidTransition. Для отмены запоминается время отмены,
+// неявка увеличивает счетчик неявок пользователя в той же транзакции.
+func ChangeBookingStatus(db *gorm.DB, booking *models.Booking, to bookingstatus.Status) error {
+	if err := bookingstatus.Transition(booking.Status, to); err != nil {
+		return err
+	}
+
+	changes := map[string]interface{}{"status": to}
+	var cancelledAt time.Time
+	if to == bookingstatus.Cancelled {
+		cancelledAt = time.Now()
+		changes["cancelled_at"] = cancelledAt
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Booking{}).
+			Where("id = ? AND status = ?", booking.ID, booking.Status).
+			Updates(changes)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("%w: booking status was changed concurrently", bookingstatus.ErrInvalidTransition)
+		}
+
+		if to == bookingstatus.NoShow {
+			return tx.Model(&models.User{}).Where("id = ?", booking.UserID).
+				Update("no_show_count", gorm.Expr("no_show_count + 1")).Error
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	booking.Status = to
+	if to == bookingstatus.Cancelled {
+		booking.CancelledAt = &cancelledAt
+	}
+	return nil
+}
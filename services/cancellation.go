@@ -0,0 +1,44 @@
+package services
+
+import (
+	"errors"
+	"restaurant-booking/models"
+	"time"
+)
+
+var ErrCancellationClosed = errors.New("booking can no longer be cancelled")
+
+// Результат проверки отмены по политике ресторана
+type CancellationDecision struct {
+	Late      bool      `json:"late"`       // отмена поздняя и отмечается в бронировании
+	FreeUntil time.Time `json:"free_until"` // до этого момента отмена бесплатна
+	Deadline  time.Time `json:"deadline"`   // после этого момента отменить нельзя
+}
+
+// Проверить параметры политики отмены ресторана
+func ValidateCancellationPolicy(freeHours, cutoffMinutes int) error {
+	verr := &ValidationError{}
+	if freeHours < 0 {
+		verr.Add("free_cancellation_hours", "must not be negative")
+	}
+	if cutoffMinutes < 0 {
+		verr.Add("cancellation_cutoff_minutes", "must not be negative")
+	}
+	return verr.OrNil()
+}
+
+// Применить политику отмены ресторана к бронированию в момент now:
+// до FreeUntil отмена бесплатна, до Deadline - поздняя, позже - запрещена
+// (ErrCancellationClosed, решение при этом заполнено для ответа клиенту).
+func EvaluateCancellation(restaurant models.Restaurant, booking models.Booking, now time.Time) (CancellationDecision, error) {
+	decision := CancellationDecision{
+		FreeUntil: booking.StartsAt.Add(-time.Duration(restaurant.FreeCancellationHours) * time.Hour),
+		Deadline:  booking.StartsAt.Add(-time.Duration(restaurant.CancellationCutoffMinutes) * time.Minute),
+	}
+
+	if !now.Before(decision.Deadline) {
+		return decision, ErrCancellationClosed
+	}
+	decision.Late = now.After(decision.FreeUntil)
+	return decision, nil
+}
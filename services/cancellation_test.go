@@ -0,0 +1,81 @@
+package services
+
+import (
+	"errors"
+	"restaurant-booking/models"
+	"testing"
+	"time"
+)
+
+func TestEvaluateCancellation(t *testing.T) {
+	startsAt := time.Date(2026, 1, 10, 19, 0, 0, 0, time.Local)
+	booking := models.Booking{StartsAt: startsAt}
+
+	tests := []struct {
+		name          string
+		freeHours     int
+		cutoffMinutes int
+		now           time.Time
+		wantLate      bool
+		wantErr       error
+	}{
+		{"free well before start", 24, 0, startsAt.Add(-48 * time.Hour), false, nil},
+		{"free at the boundary", 24, 0, startsAt.Add(-24 * time.Hour), false, nil},
+		{"late after free window", 24, 0, startsAt.Add(-23 * time.Hour), true, nil},
+		{"late just before start", 24, 0, startsAt.Add(-time.Minute), true, nil},
+		{"closed at start", 24, 0, startsAt, false, ErrCancellationClosed},
+		{"closed after start", 24, 0, startsAt.Add(time.Hour), false, ErrCancellationClosed},
+		{"late before cutoff", 24, 60, startsAt.Add(-61 * time.Minute), true, nil},
+		{"closed at cutoff", 24, 60, startsAt.Add(-60 * time.Minute), false, ErrCancellationClosed},
+		{"zero free hours is free until start", 0, 0, startsAt.Add(-time.Minute), false, nil},
+		{"zero free hours is free until cutoff", 0, 30, startsAt.Add(-31 * time.Minute), false, nil},
+		{"zero free hours closed at cutoff", 0, 30, startsAt.Add(-30 * time.Minute), false, ErrCancellationClosed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			restaurant := models.Restaurant{FreeCancellationHours: tt.freeHours, CancellationCutoffMinutes: tt.cutoffMinutes}
+			decision, err := EvaluateCancellation(restaurant, booking, tt.now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("EvaluateCancellation() error = %v, want %v", err, tt.wantErr)
+			}
+			if decision.Late != tt.wantLate {
+				t.Fatalf("EvaluateCancellation() late = %v, want %v", decision.Late, tt.wantLate)
+			}
+			if want := startsAt.Add(-time.Duration(tt.cutoffMinutes) * time.Minute); !decision.Deadline.Equal(want) {
+				t.Fatalf("EvaluateCancellation() deadline = %v, want %v", decision.Deadline, want)
+			}
+			if want := startsAt.Add(-time.Duration(tt.freeHours) * time.Hour); !decision.FreeUntil.Equal(want) {
+				t.Fatalf("EvaluateCancellation() free until = %v, want %v", decision.FreeUntil, want)
+			}
+		})
+	}
+}
+
+func TestValidateCancellationPolicy(t *testing.T) {
+	tests := []struct {
+		name                     string
+		freeHours, cutoffMinutes int
+		wantFields               []string
+	}{
+		{"defaults", 24, 0, nil},
+		{"zeros", 0, 0, nil},
+		{"negative free hours", -1, 0, []string{"free_cancellation_hours"}},
+		{"negative cutoff", 24, -5, []string{"cancellation_cutoff_minutes"}},
+		{"both negative", -1, -1, []string{"free_cancellation_hours", "cancellation_cutoff_minutes"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCancellationPolicy(tt.freeHours, tt.cutoffMinutes)
+			if len(tt.wantFields) == 0 && err != nil {
+				t.Fatalf("ValidateCancellationPolicy() = %v, want nil", err)
+			}
+			for _, field := range tt.wantFields {
+				if !hasFieldError(err, field) {
+					t.Fatalf("ValidateCancellationPolicy() = %v, want error on %q", err, field)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"errors"
+	"restaurant-booking/models"
+	"restaurant-booking/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidVerificationToken = errors.New("invalid or expired email verification token")
+	ErrEmailAlreadyVerified     = errors.New("email is already verified")
+	ErrEmailNotVerified         = errors.New("email is not verified")
+	ErrVerificationThrottled    = errors.New("verification email was sent recently")
+)
+
+// Проверить, может ли пользователь бронировать при политике,
+// требующей подтвержденный email
+func CheckEmailVerified(user models.User, required bool) error {
+	if required && user.EmailVerifiedAt == nil {
+		return ErrEmailNotVerified
+	}
+	return nil
+}
+
+// Создать токен подтверждения email. Повторно отправить письмо можно не
+// раньше чем через resendInterval после предыдущего: иначе возвращается
+// ErrVerificationThrottled и время до следующей попытки. Ранее выданные
+// токены аннулируются.
+func CreateEmailVerification(db *gorm.DB, user models.User, ttl, resendInterval time.Duration) (string, time.Duration, error) {
+	if user.EmailVerifiedAt != nil {
+		return "", 0, ErrEmailAlreadyVerified
+	}
+
+	var last models.EmailVerificationToken
+	err := db.Where("user_id = ?", user.ID).Order("created_at DESC").First(&last).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", 0, err
+	}
+	if err == nil {
+		if wait := time.Until(last.CreatedAt.Add(resendInterval)); wait > 0 {
+			return "", wait, ErrVerificationThrottled
+		}
+	}
+
+	token, err := utils.GenerateRandomToken(refreshTokenBytes)
+	if err != nil {
+		return "", 0, err
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.EmailVerificationToken{}).
+			Where("user_id = ? AND used_at IS NULL", user.ID).
+			Update("used_at", time.Now()).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.EmailVerificationToken{
+			UserID:    user.ID,
+			TokenHash: utils.HashToken(token),
+			ExpiresAt: time.Now().Add(ttl),
+		}).Error
+	})
+	if err != nil {
+		return "", 0, err
+	}
+	return token, 0, nil
+}
+
+// Подтвердить email по токену из письма. Возвращает ID пользователя.
+func VerifyEmail(db *gorm.DB, token string) (uint, error) {
+	var verification models.EmailVerificationToken
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("token_hash = ?", utils.HashToken(token)).First(&verification).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidVerificationToken
+			}
+			return err
+		}
+
+		now := time.Now()
+		result := tx.Model(&models.EmailVerificationToken{}).
+			Where("id = ? AND used_at IS NULL AND expires_at > ?", verification.ID, now).
+			Update("used_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrInvalidVerificationToken
+		}
+
+		return tx.Model(&models.User{}).Where("id = ? AND email_verified_at IS NULL", verification.UserID).
+			Update("email_verified_at", now).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return verification.UserID, nil
+}
+
+// Удалить истекшие токены подтверждения email. Возвращает число удаленных.
+func DeleteExpiredEmailVerifications(db *gorm.DB) (int64, error) {
+	result := db.Where("expires_at <= ?", time.Now()).Delete(&models.EmailVerificationToken{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"errors"
+	"restaurant-booking/models"
+	"restaurant-booking/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrTableHeld   = errors.New("table is temporarily held by another customer")
+	ErrHoldExpired = errors.New("hold has expired")
+	ErrHoldLimit   = errors.New("user already holds a table for this time")
+)
+
+// Длина токена удержания, в байтах
+const holdTokenBytes = 16
+
+// Погасить удержание при создании бронирования по нему. Удаление условно
+// по сроку действия, поэтому истекшее удержание не может быть использовано,
+// даже если фоновая очистка до него еще не дошла. Вызывается внутри
+// транзакции, создающей бронирование, чтобы при ее откате удержание вернулось.
+func RedeemHold(tx *gorm.DB, hold models.SlotHold) error {
+	result := tx.Where("id = ? AND expires_at > ?", hold.ID, time.Now()).Delete(&models.SlotHold{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrHoldExpired
+	}
+	return tx.Exec("DELETE FROM slot_hold_tables WHERE slot_hold_id = ?", hold.ID).Error
+}
+
+// Проверить, что у пользователя нет другого действующего удержания в ресторане
+// на пересекающееся время: одно удержание на ресторан и окно не дает занять
+// все столики ресторана. Удержания предложений листа ожидания не учитываются.
+func EnsureHoldAllowed(tx *gorm.DB, userID, restaurantID uint, start, end time.Time) error {
+	var active int64
+	if err := tx.Model(&models.SlotHold{}).
+		Where("user_id = ? AND restaurant_id = ? AND expires_at > ?", userID, restaurantID, time.Now()).
+		Where("starts_at < ? AND ends_at > ?", end, start).
+		Where("id NOT IN (?)", tx.Model(&models.WaitlistEntry{}).Select("offer_hold_id").Where("offer_hold_id IS NOT NULL")).
+		Count(&active).Error; err != nil {
+		return err
+	}
+	if active > 0 {
+		return ErrHoldLimit
+	}
+	return nil
+}
+
+// Досрочно снять удержание
+func ReleaseHold(db *gorm.DB, hold models.SlotHold) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM slot_hold_tables WHERE slot_hold_id = ?", hold.ID).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.SlotHold{}, hold.ID).Error
+	})
+}
+
+// Удалить истекшие удержания. Возвращает число удаленных.
+func ReleaseExpiredHolds(db *gorm.DB) (int64, error) {
+	var released int64
+	err := db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		expired := tx.Model(&models.SlotHold{}).Select("id").Where("expires_at <= ?", now)
+		if err := tx.Exec("DELETE FROM slot_hold_tables WHERE slot_hold_id IN (?)", expired).Error; err != nil {
+			return err
+		}
+		result := tx.Where("expires_at <= ?", now).Delete(&models.SlotHold{})
+		released = result.RowsAffected
+		return result.Error
+	})
+	return released, err
+}
+
+// Новый токен удержания
+func NewHoldToken() (string, error) {
+	return utils.GenerateRandomToken(holdTokenBytes)
+}
+
+// Подзапрос идентификаторов столиков, удерживаемых действующими удержаниями на интервал.
+// Время уборки учитывается так же, как для бронирований.
+func heldTableIDs(db *gorm.DB, start, end time.Time) *gorm.DB {
+	return db.Table("slot_hold_tables").Select("slot_hold_tables.table_id").
+		Joins("JOIN slot_holds ON slot_holds.id = slot_hold_tables.slot_hold_id").
+		Joins("JOIN tables ON tables.id = slot_hold_tables.table_id").
+		Joins("JOIN restaurants ON restaurants.id = tables.restaurant_id").
+		Where("slot_holds.expires_at > ?", time.Now()).
+		Where("slot_holds.starts_at < CAST(? AS timestamptz) + "+turnoverBufferSQL+" AND slot_holds.ends_at + "+turnoverBufferSQL+" > ?", end, start)
+}
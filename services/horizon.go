@@ -0,0 +1,68 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"restaurant-booking/models"
+	"restaurant-booking/utils"
+	"time"
+)
+
+var (
+	ErrBookingTooSoon     = errors.New("booking starts too soon")
+	ErrBookingTooFarAhead = errors.New("booking is too far in advance")
+)
+
+// Проверить время уборки столика между бронированиями
+func ValidateTurnoverBuffer(minutes int) error {
+	verr := &ValidationError{}
+	if minutes < 0 {
+		verr.Add("turnover_buffer_minutes", "must not be negative")
+	}
+	return verr.OrNil()
+}
+
+// Проверить настройки заблаговременности бронирования
+func ValidateBookingHorizon(minLeadMinutes, maxAdvanceDays int) error {
+	verr := &ValidationError{}
+	if minLeadMinutes < 0 {
+		verr.Add("min_lead_minutes", "must not be negative")
+	}
+	if maxAdvanceDays < 0 {
+		verr.Add("max_advance_days", "must not be negative")
+	}
+	return verr.OrNil()
+}
+
+// Допустимые моменты начала бронирования в момент now: не раньше earliest
+// и раньше latest. Нулевой latest - ограничения нет. Горизонт считается в
+// календарных днях: при MaxAdvanceDays = 60 можно бронировать на любое время
+// 60-го дня от сегодняшнего.
+func BookingHorizon(restaurant models.Restaurant, now time.Time) (time.Time, time.Time) {
+	earliest := now.Add(time.Duration(restaurant.MinLeadMinutes) * time.Minute)
+
+	var latest time.Time
+	if restaurant.MaxAdvanceDays > 0 {
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		latest = today.AddDate(0, 0, restaurant.MaxAdvanceDays+1)
+	}
+	return earliest, latest
+}
+
+// Проверить, что бронирование с началом start можно создать сейчас:
+// оно не в прошлом, не раньше минимального времени заблаговременности
+// и не дальше горизонта бронирования ресторана
+func CheckBookingHorizon(restaurant models.Restaurant, start, now time.Time) error {
+	earliest, latest := BookingHorizon(restaurant, now)
+	if start.Before(earliest) {
+		if restaurant.MinLeadMinutes == 0 {
+			return fmt.Errorf("%w: booking time is in the past", ErrBookingTooSoon)
+		}
+		return fmt.Errorf("%w: bookings must be made at least %d minutes in advance", ErrBookingTooSoon, restaurant.MinLeadMinutes)
+	}
+	if !latest.IsZero() && !start.Before(latest) {
+		return fmt.Errorf("%w: bookings can be made up to %d days ahead (until %s)", ErrBookingTooFarAhead,
+			restaurant.MaxAdvanceDays, latest.AddDate(0, 0, -1).Format(utils.DateLayout))
+	}
+	return nil
+}
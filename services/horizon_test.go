@@ -0,0 +1,64 @@
+package services
+
+import (
+	"errors"
+	"restaurant-booking/models"
+	"testing"
+	"time"
+)
+
+func TestCheckBookingHorizon(t *testing.T) {
+	now := time.Date(2026, 1, 10, 15, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name           string
+		minLeadMinutes int
+		maxAdvanceDays int
+		start          time.Time
+		wantErr        error
+	}{
+		{"no limits in the future", 0, 0, now.Add(time.Minute), nil},
+		{"no limits right now", 0, 0, now, nil},
+		{"no limits in the past", 0, 0, now.Add(-time.Minute), ErrBookingTooSoon},
+		{"no limits years ahead", 0, 0, now.AddDate(5, 0, 0), nil},
+		{"lead time satisfied", 60, 0, now.Add(time.Hour), nil},
+		{"lead time too soon", 60, 0, now.Add(59 * time.Minute), ErrBookingTooSoon},
+		{"horizon same day", 0, 60, now.Add(time.Hour), nil},
+		{"horizon last day late evening", 0, 60, time.Date(2026, 3, 11, 23, 30, 0, 0, time.Local), nil},
+		{"horizon day after last", 0, 60, time.Date(2026, 3, 12, 0, 0, 0, 0, time.Local), ErrBookingTooFarAhead},
+		{"horizon of one day allows tomorrow", 0, 1, time.Date(2026, 1, 11, 21, 0, 0, 0, time.Local), nil},
+		{"horizon of one day rejects day after", 0, 1, time.Date(2026, 1, 12, 12, 0, 0, 0, time.Local), ErrBookingTooFarAhead},
+		{"both limits inside", 120, 7, now.AddDate(0, 0, 3), nil},
+		{"both limits too soon", 120, 7, now.Add(time.Hour), ErrBookingTooSoon},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			restaurant := models.Restaurant{MinLeadMinutes: tt.minLeadMinutes, MaxAdvanceDays: tt.maxAdvanceDays}
+			err := CheckBookingHorizon(restaurant, tt.start, now)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("CheckBookingHorizon() = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CheckBookingHorizon() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBookingHorizon(t *testing.T) {
+	now := time.Date(2026, 1, 10, 15, 0, 0, 0, time.Local)
+
+	earliest, latest := BookingHorizon(models.Restaurant{MinLeadMinutes: 30}, now)
+	if !earliest.Equal(now.Add(30*time.Minute)) || !latest.IsZero() {
+		t.Fatalf("BookingHorizon() = %v, %v, want %v and no latest", earliest, latest, now.Add(30*time.Minute))
+	}
+
+	_, latest = BookingHorizon(models.Restaurant{MaxAdvanceDays: 60}, now)
+	if want := time.Date(2026, 3, 12, 0, 0, 0, 0, time.Local); !latest.Equal(want) {
+		t.Fatalf("BookingHorizon() latest = %v, want %v", latest, want)
+	}
+}
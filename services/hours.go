@@ -0,0 +1,221 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"restaurant-booking/models"
+	"restaurant-booking/utils"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrOutsideServiceHours = errors.New("booking is outside restaurant opening hours")
+
+const (
+	minutesPerDay  = 24 * 60
+	minutesPerWeek = 7 * minutesPerDay
+)
+
+// Непрерывный интервал работы ресторана
+type Interval struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Проверить формат часов работы. Оба поля пустые - ресторан работает круглосуточно.
+func ValidateOpeningHours(opening, closing string) error {
+	if opening == "" && closing == "" {
+		return nil
+	}
+	if opening == "" || closing == "" {
+		return errors.New("opening_time and closing_time must be set together")
+	}
+	if offset, err := utils.ParseClock(opening); err != nil || offset >= 24*time.Hour {
+		return fmt.Errorf("opening_time: invalid time of day: %q, expected HH:MM", opening)
+	}
+	if _, err := utils.ParseClock(closing); err != nil {
+		return fmt.Errorf("closing_time: %w", err)
+	}
+	return nil
+}
+
+// Проверить недельное расписание: корректные дни и время, отсутствие пересечений
+// интервалов, в том числе смен, переходящих через полночь на следующий день
+func ValidateWeeklySchedule(hours []models.OpeningHours) error {
+	type span struct{ start, end int }
+	spans := make([]span, 0, len(hours))
+
+	for _, h := range hours {
+		if h.Weekday < 0 || h.Weekday > 6 {
+			return fmt.Errorf("weekday must be between 0 (Sunday) and 6 (Saturday), got %d", h.Weekday)
+		}
+		if err := ValidateOpeningHours(h.OpensAt, h.ClosesAt); err != nil {
+			return err
+		}
+		opens, closes := clockMinutes(h.OpensAt), clockMinutes(h.ClosesAt)
+		if closes <= opens {
+			closes += minutesPerDay
+		}
+		base := h.Weekday * minutesPerDay
+		spans = append(spans, span{base + opens, base + closes})
+	}
+
+	for i := range spans {
+		for j := i + 1; j < len(spans); j++ {
+			a, b := spans[i], spans[j]
+			// Сравниваем также со сдвигом на неделю: смена субботы может заходить в воскресенье
+			for _, shift := range []int{-minutesPerWeek, 0, minutesPerWeek} {
+				if a.start < b.end+shift && b.start+shift < a.end {
+					return fmt.Errorf("opening hours %s-%s and %s-%s overlap",
+						hours[i].OpensAt, hours[i].ClosesAt, hours[j].OpensAt, hours[j].ClosesAt)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Интервалы работы, начинающиеся в указанный день. Исключения на дату имеют
+// приоритет: "closed" - выходной, "hours" - особые часы. Иначе, если у ресторана
+// задано недельное расписание, используется оно (день без интервалов - выходной),
+// иначе общие OpeningTime/ClosingTime.
+func ServiceIntervals(db *gorm.DB, restaurant models.Restaurant, day time.Time) ([]Interval, error) {
+	day = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+
+	var exceptions []models.DateException
+	if err := db.Where("restaurant_id = ? AND date = ? AND type IN ?", restaurant.ID, day.Format(utils.DateLayout),
+		[]string{models.DateExceptionClosed, models.DateExceptionHours}).Order("opens_at").Find(&exceptions).Error; err != nil {
+		return nil, err
+	}
+	if len(exceptions) > 0 {
+		var intervals []Interval
+		for _, e := range exceptions {
+			if e.Type == models.DateExceptionClosed {
+				return nil, nil
+			}
+			interval, err := dailyInterval(e.OpensAt, e.ClosesAt, day)
+			if err != nil {
+				return nil, err
+			}
+			intervals = append(intervals, interval)
+		}
+		return intervals, nil
+	}
+
+	var schedule []models.OpeningHours
+	if err := db.Where("restaurant_id = ?", restaurant.ID).Order("opens_at").Find(&schedule).Error; err != nil {
+		return nil, err
+	}
+
+	if len(schedule) == 0 {
+		interval, err := dailyInterval(restaurant.OpeningTime, restaurant.ClosingTime, day)
+		if err != nil {
+			return nil, err
+		}
+		return []Interval{interval}, nil
+	}
+
+	var intervals []Interval
+	for _, h := range schedule {
+		if h.Weekday != int(day.Weekday()) {
+			continue
+		}
+		interval, err := dailyInterval(h.OpensAt, h.ClosesAt, day)
+		if err != nil {
+			return nil, err
+		}
+		intervals = append(intervals, interval)
+	}
+	return intervals, nil
+}
+
+// Проверить, что интервал [start, end) целиком попадает в часы работы.
+// Учитываются и смены предыдущего дня, продолжающиеся после полуночи;
+// смежные интервалы (например, 18:00-24:00 и 00:00-02:00) считаются одним.
+func CheckServiceHours(db *gorm.DB, restaurant models.Restaurant, start, end time.Time) error {
+	day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
+
+	var all, today []Interval
+	for _, d := range []time.Time{day.AddDate(0, 0, -1), day, day.AddDate(0, 0, 1)} {
+		intervals, err := ServiceIntervals(db, restaurant, d)
+		if err != nil {
+			return err
+		}
+		if d.Equal(day) {
+			today = intervals
+		}
+		all = append(all, intervals...)
+	}
+
+	for _, interval := range mergeIntervals(all) {
+		if !start.Before(interval.Start) && !end.After(interval.End) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w (%s)", ErrOutsideServiceHours, describeIntervals(today))
+}
+
+// Объединить пересекающиеся и смежные интервалы
+func mergeIntervals(intervals []Interval) []Interval {
+	sort.Slice(intervals, func(i, j int) bool { return intervals[i].Start.Before(intervals[j].Start) })
+
+	var merged []Interval
+	for _, interval := range intervals {
+		last := len(merged) - 1
+		if last >= 0 && !interval.Start.After(merged[last].End) {
+			if interval.End.After(merged[last].End) {
+				merged[last].End = interval.End
+			}
+			continue
+		}
+		merged = append(merged, interval)
+	}
+	return merged
+}
+
+func dailyInterval(opening, closing string, day time.Time) (Interval, error) {
+	if opening == "" && closing == "" {
+		return Interval{Start: day, End: day.AddDate(0, 0, 1)}, nil
+	}
+
+	opens, err := utils.ParseClock(opening)
+	if err != nil {
+		return Interval{}, fmt.Errorf("invalid opening time: %s", opening)
+	}
+	closes, err := utils.ParseClock(closing)
+	if err != nil {
+		return Interval{}, fmt.Errorf("invalid closing time: %s", closing)
+	}
+
+	interval := Interval{Start: atClock(day, opens), End: atClock(day, closes)}
+	if !interval.End.After(interval.Start) {
+		interval.End = atClock(day.AddDate(0, 0, 1), closes)
+	}
+	return interval, nil
+}
+
+func describeIntervals(intervals []Interval) string {
+	if len(intervals) == 0 {
+		return "closed on this day"
+	}
+	parts := make([]string, 0, len(intervals))
+	for _, interval := range intervals {
+		parts = append(parts, interval.Start.Format(utils.ClockLayout)+"-"+interval.End.Format(utils.ClockLayout))
+	}
+	return "open " + strings.Join(parts, ", ")
+}
+
+// Минуты от полуночи для уже проверенного времени суток
+func clockMinutes(clock string) int {
+	offset, _ := utils.ParseClock(clock)
+	return int(offset / time.Minute)
+}
+
+// Момент времени в день day со смещением offset от полуночи
+func atClock(day time.Time, offset time.Duration) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location()).Add(offset)
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"errors"
+	"restaurant-booking/models"
+	"restaurant-booking/utils"
+	"testing"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// База без соединения: запросы не выполняются и возвращают пустой результат,
+// поэтому используются общие часы ресторана OpeningTime/ClosingTime
+func dryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(postgres.Open("host=localhost"), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatalf("open dry-run db: %v", err)
+	}
+	return db
+}
+
+// Момент времени по дате и времени суток в местной зоне
+func at(day, clock string) time.Time {
+	t, err := time.ParseInLocation(utils.DateLayout+" "+utils.ClockLayout, day+" "+clock, time.Local)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestMergeIntervals(t *testing.T) {
+	tests := []struct {
+		name      string
+		intervals []Interval
+		want      []Interval
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:      "split shift stays split",
+			intervals: []Interval{{at("2026-01-05", "18:00"), at("2026-01-05", "23:00")}, {at("2026-01-05", "12:00"), at("2026-01-05", "15:00")}},
+			want:      []Interval{{at("2026-01-05", "12:00"), at("2026-01-05", "15:00")}, {at("2026-01-05", "18:00"), at("2026-01-05", "23:00")}},
+		},
+		{
+			name:      "adjacent across midnight",
+			intervals: []Interval{{at("2026-01-06", "00:00"), at("2026-01-06", "02:00")}, {at("2026-01-05", "18:00"), at("2026-01-06", "00:00")}},
+			want:      []Interval{{at("2026-01-05", "18:00"), at("2026-01-06", "02:00")}},
+		},
+		{
+			name:      "overlapping",
+			intervals: []Interval{{at("2026-01-05", "10:00"), at("2026-01-05", "14:00")}, {at("2026-01-05", "13:00"), at("2026-01-05", "16:00")}},
+			want:      []Interval{{at("2026-01-05", "10:00"), at("2026-01-05", "16:00")}},
+		},
+		{
+			name:      "contained",
+			intervals: []Interval{{at("2026-01-05", "10:00"), at("2026-01-05", "22:00")}, {at("2026-01-05", "12:00"), at("2026-01-05", "14:00")}},
+			want:      []Interval{{at("2026-01-05", "10:00"), at("2026-01-05", "22:00")}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mergeIntervals(tt.intervals)
+			if len(got) != len(tt.want) {
+				t.Fatalf("mergeIntervals() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !got[i].Start.Equal(tt.want[i].Start) || !got[i].End.Equal(tt.want[i].End) {
+					t.Fatalf("mergeIntervals()[%d] = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCheckServiceHours(t *testing.T) {
+	db := dryRunDB(t)
+	daytime := models.Restaurant{OpeningTime: "10:00", ClosingTime: "22:00"}
+	overnight := models.Restaurant{OpeningTime: "18:00", ClosingTime: "02:00"}
+	allDay := models.Restaurant{}
+
+	tests := []struct {
+		name       string
+		restaurant models.Restaurant
+		start, end time.Time
+		wantErr    bool
+	}{
+		{"inside daytime hours", daytime, at("2026-01-05", "12:00"), at("2026-01-05", "14:00"), false},
+		{"ends at closing", daytime, at("2026-01-05", "20:00"), at("2026-01-05", "22:00"), false},
+		{"before opening", daytime, at("2026-01-05", "09:00"), at("2026-01-05", "11:00"), true},
+		{"past closing", daytime, at("2026-01-05", "21:00"), at("2026-01-05", "23:00"), true},
+		{"overnight evening", overnight, at("2026-01-05", "19:00"), at("2026-01-05", "21:00"), false},
+		{"overnight across midnight", overnight, at("2026-01-05", "23:00"), at("2026-01-06", "01:00"), false},
+		{"overnight after midnight", overnight, at("2026-01-06", "00:30"), at("2026-01-06", "02:00"), false},
+		{"overnight past closing", overnight, at("2026-01-06", "01:00"), at("2026-01-06", "03:00"), true},
+		{"overnight closed in the afternoon", overnight, at("2026-01-05", "14:00"), at("2026-01-05", "16:00"), true},
+		{"round the clock across midnight", allDay, at("2026-01-05", "23:00"), at("2026-01-06", "01:00"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckServiceHours(db, tt.restaurant, tt.start, tt.end)
+			if tt.wantErr {
+				if !errors.Is(err, ErrOutsideServiceHours) {
+					t.Fatalf("CheckServiceHours() = %v, want ErrOutsideServiceHours", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CheckServiceHours() = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestValidateWeeklySchedule(t *testing.T) {
+	tests := []struct {
+		name    string
+		hours   []models.OpeningHours
+		wantErr bool
+	}{
+		{"split shifts", []models.OpeningHours{{Weekday: 1, OpensAt: "12:00", ClosesAt: "15:00"}, {Weekday: 1, OpensAt: "18:00", ClosesAt: "23:00"}}, false},
+		{"adjacent shifts", []models.OpeningHours{{Weekday: 1, OpensAt: "12:00", ClosesAt: "15:00"}, {Weekday: 1, OpensAt: "15:00", ClosesAt: "23:00"}}, false},
+		{"overlapping shifts", []models.OpeningHours{{Weekday: 1, OpensAt: "12:00", ClosesAt: "16:00"}, {Weekday: 1, OpensAt: "15:00", ClosesAt: "23:00"}}, true},
+		{"overnight ends before next day opens", []models.OpeningHours{{Weekday: 5, OpensAt: "18:00", ClosesAt: "02:00"}, {Weekday: 6, OpensAt: "12:00", ClosesAt: "23:00"}}, false},
+		{"overnight overlaps next day", []models.OpeningHours{{Weekday: 5, OpensAt: "18:00", ClosesAt: "02:00"}, {Weekday: 6, OpensAt: "01:00", ClosesAt: "05:00"}}, true},
+		{"saturday overnight overlaps sunday", []models.OpeningHours{{Weekday: 6, OpensAt: "20:00", ClosesAt: "03:00"}, {Weekday: 0, OpensAt: "02:00", ClosesAt: "06:00"}}, true},
+		{"bad weekday", []models.OpeningHours{{Weekday: 7, OpensAt: "12:00", ClosesAt: "15:00"}}, true},
+		{"bad time", []models.OpeningHours{{Weekday: 1, OpensAt: "25:00", ClosesAt: "15:00"}}, true},
+		{"missing closing time", []models.OpeningHours{{Weekday: 1, OpensAt: "12:00"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateWeeklySchedule(tt.hours)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateWeeklySchedule() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"errors"
+	"restaurant-booking/bookingstatus"
+	"restaurant-booking/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Политики для клиентов с повторными неявками
+const (
+	NoShowPolicyNone    = "none"    // только учитывать неявки
+	NoShowPolicyBlock   = "block"   // запретить новые бронирования
+	NoShowPolicyDeposit = "deposit" // требовать депозит
+)
+
+var ErrTooManyNoShows = errors.New("booking is blocked due to repeated no-shows")
+
+// Применить политику неявок к новому бронированию пользователя. Возвращает
+// ErrTooManyNoShows, если бронировать нельзя, или true, если нужен депозит.
+func CheckNoShowPolicy(user models.User, policy string, threshold int) (bool, error) {
+	if user.NoShowCount < threshold {
+		return false, nil
+	}
+	switch policy {
+	case NoShowPolicyBlock:
+		return false, ErrTooManyNoShows
+	case NoShowPolicyDeposit:
+		return true, nil
+	}
+	return false, nil
+}
+
+// Отметить неявку у бронирований, которые так и не перешли в seated
+// спустя grace после начала. Возвращает число отмеченных.
+func MarkNoShows(db *gorm.DB, grace time.Duration) (int, error) {
+	var bookings []models.Booking
+	if err := db.Where("status IN ? AND starts_at < ?",
+		[]bookingstatus.Status{bookingstatus.Pending, bookingstatus.Confirmed}, time.Now().Add(-grace)).
+		Find(&bookings).Error; err != nil {
+		return 0, err
+	}
+
+	marked := 0
+	for i := range bookings {
+		if err := ChangeBookingStatus(db, &bookings[i], bookingstatus.NoShow); err != nil {
+			// Статус успели изменить вручную
+			if errors.Is(err, bookingstatus.ErrInvalidTransition) {
+				continue
+			}
+			return marked, err
+		}
+		marked++
+	}
+	return marked, nil
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"errors"
+	"restaurant-booking/models"
+	"restaurant-booking/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrInvalidResetToken = errors.New("invalid or expired password reset token")
+
+// Создать токен сброса пароля. Ранее выданные неиспользованные токены
+// пользователя аннулируются, действует только последнее письмо.
+func CreatePasswordReset(db *gorm.DB, userID uint, ttl time.Duration) (string, error) {
+	token, err := utils.GenerateRandomToken(refreshTokenBytes)
+	if err != nil {
+		return "", err
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.PasswordResetToken{}).
+			Where("user_id = ? AND used_at IS NULL", userID).
+			Update("used_at", time.Now()).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.PasswordResetToken{
+			UserID:    userID,
+			TokenHash: utils.HashToken(token),
+			ExpiresAt: time.Now().Add(ttl),
+		}).Error
+	})
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// Установить новый пароль по токену сброса. Токен погашается условным
+// обновлением, поэтому использовать его можно ровно один раз. Все сессии
+// пользователя отзываются, а версия токенов увеличивается.
+func ResetPassword(db *gorm.DB, token, passwordHash string) (uint, error) {
+	var reset models.PasswordResetToken
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("token_hash = ?", utils.HashToken(token)).First(&reset).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidResetToken
+			}
+			return err
+		}
+
+		now := time.Now()
+		result := tx.Model(&models.PasswordResetToken{}).
+			Where("id = ? AND used_at IS NULL AND expires_at > ?", reset.ID, now).
+			Update("used_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrInvalidResetToken
+		}
+
+		// Переход по ссылке из письма заодно подтверждает email
+		if err := tx.Model(&models.User{}).Where("id = ?", reset.UserID).Updates(map[string]interface{}{
+			"password":          passwordHash,
+			"token_version":     gorm.Expr("token_version + 1"),
+			"email_verified_at": gorm.Expr("COALESCE(email_verified_at, ?)", now),
+		}).Error; err != nil {
+			return err
+		}
+		return RevokeUserRefreshTokens(tx, reset.UserID)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return reset.UserID, nil
+}
+
+// Удалить истекшие токены сброса пароля. Возвращает число удаленных.
+func DeleteExpiredPasswordResets(db *gorm.DB) (int64, error) {
+	result := db.Where("expires_at <= ?", time.Now()).Delete(&models.PasswordResetToken{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"restaurant-booking/models"
+	"restaurant-booking/utils"
+	"time"
+)
+
+// Максимальное число повторений в одной серии
+const MaxSeriesOccurrences = 104
+
+// Правило повторения серии бронирований
+type RecurrenceRule struct {
+	Frequency string // models.SeriesWeekly или models.SeriesMonthly
+	Interval  int    // каждые Interval недель или месяцев, 0 - каждую неделю или месяц
+	Count     int    // число повторений, 0 - ограничено Until
+	Until     string // последняя дата включительно, "" - ограничено Count
+}
+
+// Проверить правило повторения и дату начала серии
+func ValidateRecurrence(startDate string, rule RecurrenceRule) error {
+	verr := &ValidationError{}
+
+	start, err := time.Parse(utils.DateLayout, startDate)
+	if err != nil {
+		verr.Add("date", "must be a date in YYYY-MM-DD format")
+	}
+	if rule.Frequency != models.SeriesWeekly && rule.Frequency != models.SeriesMonthly {
+		verr.Add("frequency", "must be one of: weekly, monthly")
+	}
+	if rule.Interval < 0 {
+		verr.Add("interval", "must not be negative")
+	}
+
+	switch {
+	case rule.Count == 0 && rule.Until == "":
+		verr.Add("count", "either count or until is required")
+	case rule.Count != 0 && rule.Until != "":
+		verr.Add("until", "count and until cannot be used together")
+	case rule.Count < 0 || rule.Count > MaxSeriesOccurrences:
+		verr.Add("count", "must be between 1 and 104")
+	case rule.Until != "":
+		until, err := time.Parse(utils.DateLayout, rule.Until)
+		if err != nil {
+			verr.Add("until", "must be a date in YYYY-MM-DD format")
+		} else if !start.IsZero() && until.Before(start) {
+			verr.Add("until", "must not be earlier than date")
+		} else if !start.IsZero() && verr.OrNil() == nil &&
+			len(expandOccurrences(start, rule, MaxSeriesOccurrences+1)) > MaxSeriesOccurrences {
+			// Серия не должна молча обрываться раньше until
+			verr.Add("until", "allows more than 104 occurrences, choose an earlier date")
+		}
+	}
+
+	return verr.OrNil()
+}
+
+// Развернуть серию в даты повторений, начиная с startDate. Ежемесячная серия
+// повторяется в тот же день месяца; месяцы без такого дня (31 число)
+// пропускаются, как в RRULE. Правило должно быть проверено ValidateRecurrence.
+func ExpandOccurrences(startDate string, rule RecurrenceRule) []string {
+	start, _ := time.Parse(utils.DateLayout, startDate)
+	return expandOccurrences(start, rule, MaxSeriesOccurrences)
+}
+
+// Развернуть серию не более чем в limit повторений
+func expandOccurrences(start time.Time, rule RecurrenceRule, limit int) []string {
+	until, _ := time.Parse(utils.DateLayout, rule.Until)
+
+	interval := rule.Interval
+	if interval <= 0 {
+		interval = 1
+	}
+
+	var dates []string
+	for step := 0; len(dates) < limit; step++ {
+		var date time.Time
+		if rule.Frequency == models.SeriesWeekly {
+			date = start.AddDate(0, 0, 7*interval*step)
+		} else {
+			date = start.AddDate(0, interval*step, 0)
+			// AddDate нормализует 31 февраля в март - такого повторения нет
+			if date.Day() != start.Day() {
+				continue
+			}
+		}
+
+		if rule.Until != "" && date.After(until) {
+			break
+		}
+		dates = append(dates, date.Format(utils.DateLayout))
+		if rule.Count > 0 && len(dates) == rule.Count {
+			break
+		}
+	}
+	return dates
+}
+
+// Повторение серии, которое не удалось забронировать, и причина
+type OccurrenceConflict struct {
+	Date   string `json:"date"`
+	Reason string `json:"reason"`
+}
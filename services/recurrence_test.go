@@ -0,0 +1,130 @@
+package services
+
+import (
+	"reflect"
+	"restaurant-booking/models"
+	"testing"
+)
+
+func TestExpandOccurrences(t *testing.T) {
+	tests := []struct {
+		name  string
+		start string
+		rule  RecurrenceRule
+		want  []string
+	}{
+		{
+			name:  "weekly count",
+			start: "2026-01-05",
+			rule:  RecurrenceRule{Frequency: models.SeriesWeekly, Interval: 1, Count: 3},
+			want:  []string{"2026-01-05", "2026-01-12", "2026-01-19"},
+		},
+		{
+			name:  "biweekly until inclusive",
+			start: "2026-01-05",
+			rule:  RecurrenceRule{Frequency: models.SeriesWeekly, Interval: 2, Until: "2026-02-02"},
+			want:  []string{"2026-01-05", "2026-01-19", "2026-02-02"},
+		},
+		{
+			name:  "zero interval defaults to every period",
+			start: "2026-03-10",
+			rule:  RecurrenceRule{Frequency: models.SeriesMonthly, Count: 2},
+			want:  []string{"2026-03-10", "2026-04-10"},
+		},
+		{
+			name:  "monthly on the 31st skips short months",
+			start: "2026-01-31",
+			rule:  RecurrenceRule{Frequency: models.SeriesMonthly, Interval: 1, Count: 4},
+			want:  []string{"2026-01-31", "2026-03-31", "2026-05-31", "2026-07-31"},
+		},
+		{
+			name:  "monthly on the 31st until end of june",
+			start: "2026-01-31",
+			rule:  RecurrenceRule{Frequency: models.SeriesMonthly, Interval: 1, Until: "2026-06-30"},
+			want:  []string{"2026-01-31", "2026-03-31", "2026-05-31"},
+		},
+		{
+			name:  "february 29 recurs only in leap years",
+			start: "2028-02-29",
+			rule:  RecurrenceRule{Frequency: models.SeriesMonthly, Interval: 12, Count: 2},
+			want:  []string{"2028-02-29", "2032-02-29"},
+		},
+		{
+			name:  "until on start date",
+			start: "2026-01-05",
+			rule:  RecurrenceRule{Frequency: models.SeriesWeekly, Interval: 1, Until: "2026-01-05"},
+			want:  []string{"2026-01-05"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ExpandOccurrences(tt.start, tt.rule)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("ExpandOccurrences(%s) = %v, want %v", tt.start, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandOccurrencesLimit(t *testing.T) {
+	rule := RecurrenceRule{Frequency: models.SeriesWeekly, Interval: 1, Until: "2030-01-01"}
+	if got := ExpandOccurrences("2026-01-05", rule); len(got) != MaxSeriesOccurrences {
+		t.Fatalf("got %d occurrences, want %d", len(got), MaxSeriesOccurrences)
+	}
+}
+
+func TestValidateRecurrence(t *testing.T) {
+	tests := []struct {
+		name      string
+		start     string
+		rule      RecurrenceRule
+		wantField string // "" - правило корректно
+	}{
+		{"weekly count", "2026-01-05", RecurrenceRule{Frequency: models.SeriesWeekly, Interval: 1, Count: 10}, ""},
+		{"max count", "2026-01-05", RecurrenceRule{Frequency: models.SeriesWeekly, Interval: 1, Count: 104}, ""},
+		{"count too large", "2026-01-05", RecurrenceRule{Frequency: models.SeriesWeekly, Interval: 1, Count: 105}, "count"},
+		{"negative count", "2026-01-05", RecurrenceRule{Frequency: models.SeriesWeekly, Interval: 1, Count: -1}, "count"},
+		{"no end", "2026-01-05", RecurrenceRule{Frequency: models.SeriesWeekly, Interval: 1}, "count"},
+		{"count and until", "2026-01-05", RecurrenceRule{Frequency: models.SeriesWeekly, Count: 2, Until: "2026-02-01"}, "until"},
+		{"bad frequency", "2026-01-05", RecurrenceRule{Frequency: "daily", Interval: 1, Count: 2}, "frequency"},
+		{"zero interval", "2026-01-05", RecurrenceRule{Frequency: models.SeriesWeekly, Count: 2}, ""},
+		{"negative interval", "2026-01-05", RecurrenceRule{Frequency: models.SeriesWeekly, Interval: -1, Count: 2}, "interval"},
+		{"bad date", "05.01.2026", RecurrenceRule{Frequency: models.SeriesWeekly, Interval: 1, Count: 2}, "date"},
+		{"bad until", "2026-01-05", RecurrenceRule{Frequency: models.SeriesWeekly, Interval: 1, Until: "soon"}, "until"},
+		{"until before date", "2026-01-05", RecurrenceRule{Frequency: models.SeriesWeekly, Interval: 1, Until: "2026-01-04"}, "until"},
+		// 104 недели от 2026-01-05: последнее допустимое повторение 2027-12-27
+		{"until at 104 occurrences", "2026-01-05", RecurrenceRule{Frequency: models.SeriesWeekly, Interval: 1, Until: "2027-12-27"}, ""},
+		{"until beyond 104 occurrences", "2026-01-05", RecurrenceRule{Frequency: models.SeriesWeekly, Interval: 1, Until: "2028-01-03"}, "until"},
+		{"monthly until far ahead", "2026-01-31", RecurrenceRule{Frequency: models.SeriesMonthly, Interval: 1, Until: "2030-12-31"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateRecurrence(tt.start, tt.rule)
+			if tt.wantField == "" {
+				if err != nil {
+					t.Fatalf("ValidateRecurrence() = %v, want nil", err)
+				}
+				return
+			}
+			if !hasFieldError(err, tt.wantField) {
+				t.Fatalf("ValidateRecurrence() = %v, want error on %q", err, tt.wantField)
+			}
+		})
+	}
+}
+
+// Ошибка валидации содержит ошибку поля field
+func hasFieldError(err error, field string) bool {
+	verr, ok := err.(*ValidationError)
+	if !ok {
+		return false
+	}
+	for _, f := range verr.Fields {
+		if f.Field == field {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"errors"
+	"restaurant-booking/models"
+	"restaurant-booking/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token was already used")
+)
+
+// Длина refresh-токена и идентификатора семейства, в байтах
+const refreshTokenBytes = 32
+
+// Выдать refresh-токен пользователю. Пустой familyID начинает новое
+// семейство - новую сессию после входа.
+func IssueRefreshToken(db *gorm.DB, userID uint, familyID string, ttl time.Duration) (string, error) {
+	token, err := utils.GenerateRandomToken(refreshTokenBytes)
+	if err != nil {
+		return "", err
+	}
+	if familyID == "" {
+		if familyID, err = utils.GenerateRandomToken(refreshTokenBytes / 2); err != nil {
+			return "", err
+		}
+	}
+
+	record := models.RefreshToken{
+		UserID:    userID,
+		TokenHash: utils.HashToken(token),
+		FamilyID:  familyID,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	if err := db.Create(&record).Error; err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// Обменять refresh-токен на новый из того же семейства. Повторное
+// использование уже обмененного токена отзывает все семейство и
+// возвращает ErrRefreshTokenReused. Возвращает новый токен и владельца.
+func RotateRefreshToken(db *gorm.DB, token string, ttl time.Duration) (string, uint, error) {
+	var record models.RefreshToken
+	if err := db.Where("token_hash = ?", utils.HashToken(token)).First(&record).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", 0, ErrInvalidRefreshToken
+		}
+		return "", 0, err
+	}
+
+	if record.RevokedAt != nil || !record.ExpiresAt.After(time.Now()) {
+		return "", 0, ErrInvalidRefreshToken
+	}
+	if record.UsedAt != nil {
+		return "", 0, revokeReusedFamily(db, record.FamilyID)
+	}
+
+	// Условное обновление: из двух параллельных обменов одного токена
+	// успешен только первый, второй считается повторным использованием
+	result := db.Model(&models.RefreshToken{}).
+		Where("id = ? AND used_at IS NULL AND revoked_at IS NULL", record.ID).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return "", 0, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return "", 0, revokeReusedFamily(db, record.FamilyID)
+	}
+
+	next, err := IssueRefreshToken(db, record.UserID, record.FamilyID, ttl)
+	if err != nil {
+		return "", 0, err
+	}
+	return next, record.UserID, nil
+}
+
+// Отозвать сессию, которой принадлежит refresh-токен (выход из системы).
+// Неизвестный токен не считается ошибкой.
+func RevokeRefreshToken(db *gorm.DB, token string) error {
+	var record models.RefreshToken
+	if err := db.Where("token_hash = ?", utils.HashToken(token)).First(&record).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	return revokeFamily(db, record.FamilyID)
+}
+
+// Отозвать все сессии пользователя
+func RevokeUserRefreshTokens(db *gorm.DB, userID uint) error {
+	return db.Model(&models.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// Удалить refresh-токены, срок которых истек. Возвращает число удаленных.
+func DeleteExpiredRefreshTokens(db *gorm.DB) (int64, error) {
+	result := db.Where("expires_at <= ?", time.Now()).Delete(&models.RefreshToken{})
+	return result.RowsAffected, result.Error
+}
+
+func revokeFamily(db *gorm.DB, familyID string) error {
+	return db.Model(&models.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now()).Error
+}
+
+func revokeReusedFamily(db *gorm.DB, familyID string) error {
+	if err := revokeFamily(db, familyID); err != nil {
+		return err
+	}
+	return ErrRefreshTokenReused
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"fmt"
+	"restaurant-booking/models"
+	"restaurant-booking/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	MinSlotInterval = 15  // минимальный шаг сетки слотов, который может запросить клиент, в минутах
+	MaxSlotsPerDay  = 192 // не более стольких слотов проверяется за один запрос
+)
+
+// Время начала бронирования и столики, свободные на всю его длительность.
+// Для заведений, работающих после полуночи, дата слота может быть следующей.
+type Slot struct {
+	Date         string                    `json:"date"`
+	Time         string                    `json:"time"`
+	Available    int                       `json:"available"`
+	Tables       []models.Table            `json:"tables"`
+	Combinations []models.TableCombination `json:"combinations,omitempty"`
+}
+
+// Построить сетку слотов на дату: для каждого интервала работы - каждые
+// interval минут от открытия до последнего времени, при котором бронирование
+// длительностью duration заканчивается не позже закрытия. Слоты вне горизонта
+// бронирования ресторана (прошедшие, слишком близкие или далекие) пропускаются.
+// Проверяется не более MaxSlotsPerDay слотов, остальные отбрасываются.
+func FindSlots(db *gorm.DB, restaurant models.Restaurant, date string, guests, duration, interval int) ([]Slot, error) {
+	day, err := time.ParseInLocation(utils.DateLayout, date, time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date: %s", date)
+	}
+
+	intervals, err := ServiceIntervals(db, restaurant, day)
+	if err != nil {
+		return nil, err
+	}
+
+	length := time.Duration(duration) * time.Minute
+	step := time.Duration(interval) * time.Minute
+
+	now := time.Now()
+	slots := []Slot{}
+	checked := 0
+	for _, service := range intervals {
+		for start := service.Start; !start.Add(length).After(service.End); start = start.Add(step) {
+			// Каждый слот - несколько запросов к базе, их число ограничено
+			if checked == MaxSlotsPerDay {
+				return slots, nil
+			}
+			checked++
+			if CheckBookingHorizon(restaurant, start, now) != nil {
+				continue
+			}
+			query := AvailabilityQuery{
+				RestaurantID: restaurant.ID,
+				Start:        start,
+				End:          start.Add(length),
+				Guests:       guests,
+			}
+			tables, err := FindFreeTables(db, query)
+			if err != nil {
+				return nil, err
+			}
+			combinations, err := FindFreeCombinations(db, query)
+			if err != nil {
+				return nil, err
+			}
+
+			slots = append(slots, Slot{
+				Date:         start.Format(utils.DateLayout),
+				Time:         start.Format(utils.ClockLayout),
+				Available:    len(tables) + len(combinations),
+				Tables:       tables,
+				Combinations: combinations,
+			})
+		}
+	}
+	return slots, nil
+}
@@ -0,0 +1,45 @@
+package services
+
+import (
+	"restaurant-booking/models"
+
+	"gorm.io/gorm"
+)
+
+// Проверить назначаемую роль: администратор ресторана должен быть
+// привязан к ресторану
+func ValidateUserRole(role string, restaurantID *uint) error {
+	verr := &ValidationError{}
+	switch role {
+	case models.RoleCustomer, models.RoleAdmin:
+	case models.RoleRestaurantAdmin:
+		if restaurantID == nil {
+			verr.Add("restaurant_id", "is required for restaurant_admin")
+		}
+	default:
+		verr.Add("role", "must be one of customer, restaurant_admin, admin")
+	}
+	return verr.OrNil()
+}
+
+// Сменить роль пользователя. Версия токенов увеличивается, поэтому
+// выданные ранее access-токены со старой ролью перестают приниматься.
+func ChangeUserRole(db *gorm.DB, user *models.User, role string, restaurantID *uint) error {
+	if role != models.RoleRestaurantAdmin {
+		restaurantID = nil
+	}
+
+	err := db.Model(&models.User{}).Where("id = ?", user.ID).Updates(map[string]interface{}{
+		"role":          role,
+		"restaurant_id": restaurantID,
+		"token_version": gorm.Expr("token_version + 1"),
+	}).Error
+	if err != nil {
+		return err
+	}
+
+	user.Role = role
+	user.RestaurantID = restaurantID
+	user.TokenVersion++
+	return nil
+}
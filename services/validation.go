@@ -0,0 +1,89 @@
+package services
+
+import (
+	"fmt"
+	"restaurant-booking/models"
+	"strings"
+)
+
+// Ошибка валидации конкретного поля запроса
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Набор ошибок валидации по полям
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		parts = append(parts, f.Field+": "+f.Message)
+	}
+	return "validation failed: " + strings.Join(parts, "; ")
+}
+
+func (e *ValidationError) Add(field, message string) {
+	e.Fields = append(e.Fields, FieldError{Field: field, Message: message})
+}
+
+// Вернуть ошибку, только если были добавлены поля
+func (e *ValidationError) OrNil() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Проверить ограничения размера компании, задаваемые рестораном
+func ValidatePartyLimits(minParty, maxParty int) error {
+	verr := &ValidationError{}
+	if minParty < 0 {
+		verr.Add("min_party_size", "must not be negative")
+	}
+	if maxParty < 0 {
+		verr.Add("max_party_size", "must not be negative")
+	}
+	if maxParty > 0 && minParty > maxParty {
+		verr.Add("max_party_size", "must not be less than min_party_size")
+	}
+	return verr.OrNil()
+}
+
+// Проверить размер компании по правилам ресторана
+func ValidatePartySize(restaurant models.Restaurant, guests int) error {
+	verr := &ValidationError{}
+	checkPartySize(verr, restaurant, guests)
+	return verr.OrNil()
+}
+
+// Проверить размер компании и длительность для бронирования столика
+// (или комбинации столиков) с указанной вместимостью и минимальной загрузкой
+func ValidateBookingParty(restaurant models.Restaurant, capacity, minGuests, guests, duration int) error {
+	verr := &ValidationError{}
+	checkPartySize(verr, restaurant, guests)
+
+	if guests > capacity {
+		verr.Add("guests", fmt.Sprintf("table seats at most %d guests", capacity))
+	}
+	if minGuests > 0 && guests < minGuests {
+		verr.Add("guests", fmt.Sprintf("table requires at least %d guests", minGuests))
+	}
+	if duration <= 0 {
+		verr.Add("duration", "must be positive")
+	}
+	return verr.OrNil()
+}
+
+func checkPartySize(verr *ValidationError, restaurant models.Restaurant, guests int) {
+	switch {
+	case guests <= 0:
+		verr.Add("guests", "must be positive")
+	case restaurant.MinPartySize > 0 && guests < restaurant.MinPartySize:
+		verr.Add("guests", fmt.Sprintf("restaurant accepts parties of at least %d guests", restaurant.MinPartySize))
+	case restaurant.MaxPartySize > 0 && guests > restaurant.MaxPartySize:
+		verr.Add("guests", fmt.Sprintf("restaurant accepts parties of at most %d guests", restaurant.MaxPartySize))
+	}
+}
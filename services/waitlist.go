@@ -0,0 +1,297 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"restaurant-booking/bookingstatus"
+	"restaurant-booking/models"
+	"restaurant-booking/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrWaitlistNoOffer      = errors.New("waitlist entry has no active offer")
+	ErrWaitlistOfferExpired = errors.New("waitlist offer has expired")
+)
+
+// Проверить окно ожидания: корректные дата и время, начало не позже конца
+func ValidateWaitlistWindow(date, timeFrom, timeTo string) error {
+	verr := &ValidationError{}
+	if _, err := time.Parse(utils.DateLayout, date); err != nil {
+		verr.Add("date", "must be a date in YYYY-MM-DD format")
+	}
+	from, errFrom := time.Parse(utils.ClockLayout, timeFrom)
+	if errFrom != nil {
+		verr.Add("time_from", "must be a time of day in HH:MM format")
+	}
+	to, errTo := time.Parse(utils.ClockLayout, timeTo)
+	if errTo != nil {
+		verr.Add("time_to", "must be a time of day in HH:MM format")
+	}
+	if errFrom == nil && errTo == nil && to.Before(from) {
+		verr.Add("time_to", "must not be earlier than time_from")
+	}
+	return verr.OrNil()
+}
+
+// Предложить слот отмененного бронирования первой подходящей записи листа
+// ожидания: та же дата, время начала попадает в окно клиента, компания
+// помещается за освободившиеся столики, и они свободны на нужную длительность.
+// На время предложения столики удерживаются для клиента, чтобы их не заняли
+// другие. Возвращает nil, если подходящих записей нет или слот уже занят
+// через лист ожидания.
+func OfferFreedSlot(db *gorm.DB, booking models.Booking, claimTTL time.Duration) (*models.WaitlistEntry, error) {
+	if err := db.Preload("Restaurant").Preload("Tables").First(&booking, booking.ID).Error; err != nil {
+		return nil, err
+	}
+	if booking.Status != bookingstatus.Cancelled || len(booking.Tables) == 0 ||
+		CheckBookingHorizon(booking.Restaurant, booking.StartsAt, time.Now()) != nil {
+		return nil, nil
+	}
+
+	var claimed int64
+	if err := db.Model(&models.WaitlistEntry{}).
+		Where("offered_booking_id = ? AND status = ?", booking.ID, models.WaitlistClaimed).
+		Count(&claimed).Error; err != nil {
+		return nil, err
+	}
+	if claimed > 0 {
+		return nil, nil
+	}
+
+	capacity, minGuests, err := bookingCapacity(db, booking)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []models.WaitlistEntry
+	if err := db.Where("restaurant_id = ? AND date = ? AND status = ?", booking.RestaurantID, booking.Date, models.WaitlistWaiting).
+		Where("time_from <= ? AND time_to >= ?", booking.Time, booking.Time).
+		Where("guests <= ? AND guests >= ?", capacity, minGuests).
+		Order("created_at, id").
+		Find(&entries).Error; err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		start, end, err := utils.ParseBookingWindow(booking.Date, booking.Time, entryDuration(entry))
+		if err != nil {
+			continue
+		}
+		if err := EnsureTablesFree(db, TableIDs(booking.Tables), start, end, 0); err != nil {
+			if errors.Is(err, ErrTableUnavailable) || errors.Is(err, ErrTableBlocked) || errors.Is(err, ErrTableHeld) {
+				continue
+			}
+			return nil, err
+		}
+		if err := CheckServiceHours(db, booking.Restaurant, start, end); err != nil {
+			if errors.Is(err, ErrOutsideServiceHours) {
+				continue
+			}
+			return nil, err
+		}
+
+		hold, err := offerEntry(db, &entry, booking, start, end, time.Now().Add(claimTTL))
+		if errors.Is(err, errEntryChanged) {
+			// Запись успели отменить или предложить другой слот
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		entry.OfferHoldID = &hold.ID
+		return &entry, nil
+	}
+
+	return nil, nil
+}
+
+var errEntryChanged = errors.New("waitlist entry was changed concurrently")
+
+// Перевести запись в статус offered и удержать для нее столики до expiresAt
+func offerEntry(db *gorm.DB, entry *models.WaitlistEntry, booking models.Booking, start, end, expiresAt time.Time) (models.SlotHold, error) {
+	token, err := NewHoldToken()
+	if err != nil {
+		return models.SlotHold{}, err
+	}
+
+	hold := models.SlotHold{
+		Token:              token,
+		UserID:             entry.UserID,
+		RestaurantID:       booking.RestaurantID,
+		TableID:            booking.TableID,
+		TableCombinationID: booking.TableCombinationID,
+		Date:               booking.Date,
+		Time:               booking.Time,
+		Duration:           entryDuration(*entry),
+		Guests:             entry.Guests,
+		StartsAt:           start,
+		EndsAt:             end,
+		ExpiresAt:          expiresAt,
+		Tables:             booking.Tables,
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Tables.*").Create(&hold).Error; err != nil {
+			return err
+		}
+		result := tx.Model(&models.WaitlistEntry{}).
+			Where("id = ? AND status = ?", entry.ID, models.WaitlistWaiting).
+			Updates(map[string]interface{}{
+				"status":             models.WaitlistOffered,
+				"offered_booking_id": booking.ID,
+				"offered_time":       booking.Time,
+				"offer_expires_at":   expiresAt,
+				"offer_hold_id":      hold.ID,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errEntryChanged
+		}
+		return nil
+	})
+	if err != nil {
+		return models.SlotHold{}, err
+	}
+
+	entry.Status = models.WaitlistOffered
+	entry.OfferedBookingID = &booking.ID
+	entry.OfferedTime = booking.Time
+	entry.OfferExpiresAt = &expiresAt
+	return hold, nil
+}
+
+// Снять удержание столиков, сделанное для предложения записи
+func ReleaseWaitlistOffer(db *gorm.DB, entry models.WaitlistEntry) error {
+	if entry.OfferHoldID == nil {
+		return nil
+	}
+	return ReleaseHold(db, models.SlotHold{ID: *entry.OfferHoldID})
+}
+
+// Принять предложение из листа ожидания: создать бронирование на предложенные
+// столики и время, погасив их удержание. depositRequired - результат политики
+// неявок для клиента. Вызывается внутри сериализуемой транзакции.
+func ClaimWaitlistOffer(tx *gorm.DB, entry models.WaitlistEntry, depositRequired bool) (models.Booking, error) {
+	if entry.Status != models.WaitlistOffered || entry.OfferedBookingID == nil {
+		return models.Booking{}, ErrWaitlistNoOffer
+	}
+	if entry.OfferExpiresAt != nil && time.Now().After(*entry.OfferExpiresAt) {
+		return models.Booking{}, ErrWaitlistOfferExpired
+	}
+
+	var freed models.Booking
+	if err := tx.Preload("Tables").First(&freed, *entry.OfferedBookingID).Error; err != nil {
+		return models.Booking{}, err
+	}
+
+	booking := models.Booking{
+		UserID:             entry.UserID,
+		RestaurantID:       entry.RestaurantID,
+		TableID:            freed.TableID,
+		TableCombinationID: freed.TableCombinationID,
+		Date:               entry.Date,
+		Time:               entry.OfferedTime,
+		Duration:           entryDuration(entry),
+		Guests:             entry.Guests,
+		Notes:              entry.Notes,
+		Status:             bookingstatus.Pending,
+		DepositRequired:    depositRequired,
+		Tables:             freed.Tables,
+	}
+	if err := booking.SetWindow(); err != nil {
+		return models.Booking{}, err
+	}
+	if entry.OfferHoldID != nil {
+		if err := RedeemHold(tx, models.SlotHold{ID: *entry.OfferHoldID}); err != nil {
+			if errors.Is(err, ErrHoldExpired) {
+				return models.Booking{}, ErrWaitlistOfferExpired
+			}
+			return models.Booking{}, err
+		}
+	}
+	if err := EnsureTablesFree(tx, TableIDs(booking.Tables), booking.StartsAt, booking.EndsAt, 0); err != nil {
+		return models.Booking{}, err
+	}
+	if err := tx.Omit("Tables.*").Create(&booking).Error; err != nil {
+		return models.Booking{}, err
+	}
+
+	result := tx.Model(&models.WaitlistEntry{}).
+		Where("id = ? AND status = ?", entry.ID, models.WaitlistOffered).
+		Updates(map[string]interface{}{"status": models.WaitlistClaimed, "booking_id": booking.ID})
+	if result.Error != nil {
+		return models.Booking{}, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return models.Booking{}, fmt.Errorf("%w: entry was changed concurrently", ErrWaitlistNoOffer)
+	}
+	return booking, nil
+}
+
+// Истечь непринятые предложения и передать их слоты следующим в очереди,
+// а также закрыть ожидания на прошедшие даты. Возвращает число истекших предложений.
+func ExpireWaitlistOffers(db *gorm.DB, claimTTL time.Duration) (int, error) {
+	now := time.Now()
+
+	var offered []models.WaitlistEntry
+	if err := db.Where("status = ? AND offer_expires_at < ?", models.WaitlistOffered, now).
+		Find(&offered).Error; err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, entry := range offered {
+		result := db.Model(&models.WaitlistEntry{}).
+			Where("id = ? AND status = ?", entry.ID, models.WaitlistOffered).
+			Update("status", models.WaitlistExpired)
+		if result.Error != nil {
+			return expired, result.Error
+		}
+		if result.RowsAffected == 0 || entry.OfferedBookingID == nil {
+			continue
+		}
+		expired++
+
+		if err := ReleaseWaitlistOffer(db, entry); err != nil {
+			return expired, err
+		}
+
+		if _, err := OfferFreedSlot(db, models.Booking{ID: *entry.OfferedBookingID}, claimTTL); err != nil {
+			return expired, err
+		}
+	}
+
+	err := db.Model(&models.WaitlistEntry{}).
+		Where("status = ? AND date < ?", models.WaitlistWaiting, now.Format(utils.DateLayout)).
+		Update("status", models.WaitlistExpired).Error
+	return expired, err
+}
+
+// Вместимость столиков бронирования: комбинации или основного столика
+func bookingCapacity(db *gorm.DB, booking models.Booking) (int, int, error) {
+	if booking.TableCombinationID != nil {
+		var combination models.TableCombination
+		if err := db.First(&combination, *booking.TableCombinationID).Error; err != nil {
+			return 0, 0, err
+		}
+		return combination.Capacity, combination.MinGuests, nil
+	}
+	var table models.Table
+	if err := db.First(&table, booking.TableID).Error; err != nil {
+		return 0, 0, err
+	}
+	return table.Capacity, table.MinGuests, nil
+}
+
+func entryDuration(entry models.WaitlistEntry) int {
+	if entry.Duration > 0 {
+		return entry.Duration
+	}
+	return models.DefaultBookingDuration
+}
@@ -10,10 +10,21 @@ import (
 
 var jwtSecret []byte
 
+// Время жизни access-токена; продлевается через refresh-токен
+var accessTokenTTL = 15 * time.Minute
+
 func SetJWTSecret(secret string) {
 	jwtSecret = []byte(secret)
 }
 
+func SetAccessTokenTTL(ttl time.Duration) {
+	accessTokenTTL = ttl
+}
+
+func AccessTokenTTL() time.Duration {
+	return accessTokenTTL
+}
+
 func HashPassword(password string) (string, error) {
 	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	return string(bytes), err
@@ -24,42 +35,44 @@ func CheckPasswordHash(password, hash string) bool {
 	return err == nil
 }
 
-func GenerateToken(userID uint, username string) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"user_id":  userID,
-		"username": username,
-		"exp":      time.Now().Add(time.Hour * 24).Unix(),
-		"iat":      time.Now().Unix(),
-	})
+// Утверждения access-токена. Роль и ресторан берутся из токена без
+// обращения к базе; TokenVersion сверяется с пользователем, чтобы
+// смена роли отзывала ранее выданные токены.
+type Claims struct {
+	UserID       uint   `json:"user_id"`
+	Username     string `json:"username"`
+	Role         string `json:"role"`
+	RestaurantID *uint  `json:"restaurant_id,omitempty"`
+	TokenVersion int    `json:"token_version"`
+	jwt.RegisteredClaims
+}
+
+func GenerateToken(claims Claims) (string, error) {
+	now := time.Now()
+	claims.IssuedAt = jwt.NewNumericDate(now)
+	claims.ExpiresAt = jwt.NewNumericDate(now.Add(accessTokenTTL))
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString(jwtSecret)
 }
 
-func ValidateToken(tokenString string) (*jwt.Token, error) {
+func ParseToken(tokenString string) (*Claims, error) {
 	if len(jwtSecret) == 0 {
 		return nil, errors.New("JWT secret not set")
 	}
-	
-	return jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, errors.New("unexpected signing method")
 		}
 		return jwtSecret, nil
 	})
-}
-
-func ExtractUserIDFromToken(tokenString string) (uint, error) {
-	token, err := ValidateToken(tokenString)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
-
-	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		if userID, exists := claims["user_id"]; exists {
-			if id, ok := userID.(float64); ok {
-				return uint(id), nil
-			}
-		}
+	if !token.Valid || claims.UserID == 0 {
+		return nil, errors.New("invalid token claims")
 	}
-	
-	return 0, errors.New("invalid token claims")
-} 
\ No newline at end of file
+	return claims, nil
+}
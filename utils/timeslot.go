@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	DateLayout  = "2006-01-02"
+	ClockLayout = "15:04"
+)
+
+// Разобрать дату, время начала и длительность бронирования в интервал [start, end)
+func ParseBookingWindow(date, clock string, duration int) (time.Time, time.Time, error) {
+	if duration <= 0 {
+		return time.Time{}, time.Time{}, errors.New("duration must be positive")
+	}
+
+	start, err := time.ParseInLocation(DateLayout+" "+ClockLayout, date+" "+clock, time.Local)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid date or time: %s %s", date, clock)
+	}
+
+	return start, start.Add(time.Duration(duration) * time.Minute), nil
+}
+
+// Разобрать время суток "HH:MM" в смещение от полуночи; "24:00" означает конец суток
+func ParseClock(clock string) (time.Duration, error) {
+	if clock == "24:00" {
+		return 24 * time.Hour, nil
+	}
+	t, err := time.Parse(ClockLayout, clock)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day: %q, expected HH:MM", clock)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}